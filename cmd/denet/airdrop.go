@@ -0,0 +1,105 @@
+package main
+
+import (
+	"denet/internal/airdrop"
+	"denet/internal/config"
+	"denet/internal/lib/models"
+	"denet/internal/storage/postgres"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const airdropUsage = `usage:
+  denet airdrop build --snapshot <id> [--decimals 0] [--out claims.json]`
+
+// runAirdrop implements the "denet airdrop" subcommand.
+func runAirdrop(args []string) int {
+	if len(args) == 0 || args[0] != "build" {
+		fmt.Fprintln(os.Stderr, airdropUsage)
+		return 2
+	}
+	return runAirdropBuild(args[1:])
+}
+
+// runAirdropBuild builds the Merkle tree of a snapshot, stores its root and
+// claims, and writes the airdrop with all claims as JSON to stdout or to the
+// --out file. Only users who linked a wallet through /wallet/verify get a
+// claim.
+func runAirdropBuild(args []string) int {
+	fs := flag.NewFlagSet("airdrop build", flag.ContinueOnError)
+	snapshotID := fs.Int64("snapshot", 0, "balance snapshot to build the airdrop from")
+	decimals := fs.Int("decimals", 0, "token decimals the balances are scaled by")
+	outFile := fs.String("out", "", "write the claims to this file instead of stdout")
+	fs.Usage = func() { fmt.Fprintln(os.Stderr, airdropUsage) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *snapshotID <= 0 {
+		fmt.Fprintln(os.Stderr, airdropUsage)
+		return 2
+	}
+
+	cfg := config.MustLoad()
+	storage, err := postgres.New(cfg.StoragePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to init storage:", err)
+		return 1
+	}
+
+	snapshot, err := storage.GetSnapshot(*snapshotID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to read snapshot:", err)
+		return 1
+	}
+	wallets, err := storage.GetWalletAddresses()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to read wallets:", err)
+		return 1
+	}
+
+	drop, claims, skipped, err := airdrop.Build(snapshot, wallets, *decimals)
+	for _, entry := range skipped {
+		fmt.Fprintf(os.Stderr, "skipped %s (user %d): no linked wallet\n", entry.Username, entry.UserId)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to build airdrop:", err)
+		return 1
+	}
+
+	drop.CreatedAt = time.Now().UTC()
+	if err := storage.CreateAirdrop(drop, claims); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to save airdrop:", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "airdrop %d: %d claims, root %s\n", drop.Id, drop.Claims, drop.Root)
+
+	var out io.Writer = os.Stdout
+	if *outFile != "" {
+		f, err := os.Create(*outFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+
+	for i := range claims {
+		claims[i].AirdropId = drop.Id
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(struct {
+		*models.Airdrop
+		ClaimList []models.AirdropClaim `json:"claim_list"`
+	}{drop, claims})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -3,15 +3,40 @@ package main
 import (
 	"context"
 	"denet/internal/config"
+	"denet/internal/http-server/handlers/accounts"
+	"denet/internal/http-server/handlers/airdrop"
+	"denet/internal/http-server/handlers/balance"
+	boostlist "denet/internal/http-server/handlers/boosts"
+	"denet/internal/http-server/handlers/history"
 	"denet/internal/http-server/handlers/info"
 	"denet/internal/http-server/handlers/leaderboard"
 	"denet/internal/http-server/handlers/login"
+	"denet/internal/http-server/handlers/redemptions"
 	"denet/internal/http-server/handlers/referrer"
+	"denet/internal/http-server/handlers/rewards/codes"
+	rewardlist "denet/internal/http-server/handlers/rewards/list"
+	"denet/internal/http-server/handlers/rewards/redeem"
+	rewardsave "denet/internal/http-server/handlers/rewards/save"
+	"denet/internal/http-server/handlers/snapshots"
+	"denet/internal/http-server/handlers/submissions"
 	"denet/internal/http-server/handlers/task"
+	"denet/internal/http-server/handlers/tasks/list"
+	"denet/internal/http-server/handlers/tasks/prerequisites"
+	tasksave "denet/internal/http-server/handlers/tasks/save"
+	"denet/internal/http-server/handlers/transfer"
+	"denet/internal/http-server/handlers/users/adjust"
 	"denet/internal/http-server/handlers/users/save"
+	"denet/internal/http-server/handlers/users/tier"
+	"denet/internal/http-server/handlers/wallet"
 	middlewares "denet/internal/http-server/middleware"
+	"denet/internal/jobs"
+	"denet/internal/lib/boost"
 	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
 	"denet/internal/storage/postgres"
+	"denet/internal/verifier"
+	"denet/internal/verifier/telegram"
+	"denet/internal/verifier/twitter"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -31,6 +56,17 @@ const (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "tasks":
+			os.Exit(runTasks(os.Args[2:]))
+		case "reconcile":
+			os.Exit(runReconcile(os.Args[2:]))
+		case "airdrop":
+			os.Exit(runAirdrop(os.Args[2:]))
+		}
+	}
+
 	cfg := config.MustLoad()
 	log := setupLogger(cfg.Env)
 	storage, err := postgres.New(cfg.StoragePath)
@@ -38,6 +74,26 @@ func main() {
 		log.Error("Failed to init storage", sl.Err(err))
 		os.Exit(1)
 	}
+	if err := storage.SyncCurrencies(currencies(cfg)); err != nil {
+		log.Error("Failed to sync currencies", sl.Err(err))
+		os.Exit(1)
+	}
+	rules := boosts(cfg)
+	if err := rules.Validate(); err != nil {
+		log.Error("Invalid boosts config", sl.Err(err))
+		os.Exit(1)
+	}
+	storage.SetBoosts(rules)
+
+	verifiers := verifier.NewRegistry()
+	verifiers.Register(verifier.TypeNone, verifier.None{})
+	verifiers.Register(verifier.TypeManual, verifier.Manual{})
+	verifiers.Register(verifier.TypeTelegramSubscription, telegram.New(cfg.Telegram, storage))
+	twitterVerifier := twitter.New(cfg.Twitter, storage)
+	verifiers.Register(verifier.TypeTwitterFollow, twitterVerifier)
+	verifiers.Register(verifier.TypeTwitterRetweet, twitterVerifier)
+	verifiers.Register(verifier.TypeTwitterLike, twitterVerifier)
+
 	router := chi.NewRouter()
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
@@ -50,8 +106,47 @@ func main() {
 		r.Post("/create", save.New(log, storage))
 		r.Get("/{id}/status", info.NewUserInfo(log, storage))
 		r.Get("/leaderboard", leaderboard.NewLeaderboard(log, storage))
-		r.Post("/{id}/task/complete", task.NewTask(log, storage))
+		r.Post("/{id}/task/complete", task.NewTask(log, storage, verifiers))
 		r.Post("/{id}/task/referrer", referrer.NewReferalTask(log, storage))
+		r.Get("/{id}/tasks", list.NewList(log, storage))
+		r.Get("/{id}/history", history.NewHistory(log, storage))
+		r.Get("/{id}/balance", balance.NewBalance(log, storage))
+		r.Get("/{id}/airdrop-proof", airdrop.NewProof(log, storage))
+		r.Post("/{id}/wallet/challenge", wallet.NewChallenge(log, storage))
+		r.Post("/{id}/wallet/verify", wallet.NewVerify(log, storage))
+		r.Post("/{id}/telegram", accounts.NewLinkTelegram(log, storage, cfg.Telegram))
+		r.Post("/{id}/transfer", transfer.New(log, storage, cfg.Transfers))
+		r.Post("/{id}/rewards/{rewardId}/redeem", redeem.NewRedeem(log, storage))
+		r.Get("/{id}/redemptions", redemptions.NewUserList(log, storage))
+	})
+
+	router.Route("/rewards", func(r chi.Router) {
+		r.Use(middlewares.ValidateJWT)
+		r.Get("/", rewardlist.NewList(log, storage))
+	})
+
+	router.Route("/boosts", func(r chi.Router) {
+		r.Use(middlewares.ValidateJWT)
+		r.Get("/", boostlist.NewList(log, rules))
+	})
+
+	router.Route("/admin/", func(r chi.Router) {
+		r.Use(middlewares.ValidateJWT)
+		r.Use(middlewares.RequireAdmin)
+		r.Post("/tasks", tasksave.New(log, storage))
+		r.Put("/tasks/{id}/prerequisites", prerequisites.NewSetPrerequisites(log, storage))
+		r.Get("/submissions", submissions.NewList(log, storage))
+		r.Post("/submissions/{id}/approve", submissions.NewApprove(log, storage))
+		r.Post("/submissions/{id}/reject", submissions.NewReject(log, storage))
+		r.Post("/rewards", rewardsave.New(log, storage))
+		r.Post("/rewards/{id}/codes", codes.NewUpload(log, storage))
+		r.Get("/redemptions", redemptions.NewList(log, storage))
+		r.Post("/redemptions/{id}/status", redemptions.NewUpdateStatus(log, storage))
+		r.Post("/users/{id}/adjust", adjust.New(log, storage))
+		r.Put("/users/{id}/tier", tier.New(log, storage))
+		r.Put("/users/{id}/twitter", accounts.NewLinkTwitter(log, storage))
+		r.Post("/snapshots", snapshots.NewCreate(log, storage))
+		r.Get("/snapshots/{id}", snapshots.NewGet(log, storage))
 	})
 
 	// router.Post("/users", save.New(log, storage))
@@ -73,6 +168,11 @@ func main() {
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go jobs.Run(jobsCtx, log, "expiry", cfg.Jobs.ExpiryInterval, jobs.ExpirePoints(log, storage))
+	go jobs.Run(jobsCtx, log, "reconcile", cfg.Jobs.ReconcileInterval, jobs.Reconcile(log, storage, cfg.Jobs.ReconcileRepair))
+
 	go func() {
 		if err := srv.ListenAndServe(); err != nil {
 			log.Error("failed to start server")
@@ -83,6 +183,7 @@ func main() {
 
 	<-done
 	log.Info("stopping server")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -99,6 +200,35 @@ func main() {
 	// log.Error("server stopped")
 }
 
+// currencies returns the configured currencies, always including points.
+func currencies(cfg *config.Config) []models.Currency {
+	list := []models.Currency{{Code: models.CurrencyPoints, Title: "Points"}}
+	for _, currency := range cfg.Currencies {
+		if currency.Code == models.CurrencyPoints {
+			list[0].Title = currency.Title
+			continue
+		}
+		list = append(list, models.Currency{Code: currency.Code, Title: currency.Title})
+	}
+	return list
+}
+
+// boosts returns the configured multiplier rules.
+func boosts(cfg *config.Config) boost.Rules {
+	rules := make(boost.Rules, 0, len(cfg.Boosts))
+	for _, b := range cfg.Boosts {
+		rules = append(rules, boost.Rule{
+			Name:       b.Name,
+			Multiplier: b.Multiplier,
+			StartsAt:   b.StartsAt,
+			EndsAt:     b.EndsAt,
+			Tasks:      b.Tasks,
+			Tiers:      b.Tiers,
+		})
+	}
+	return rules
+}
+
 func setupLogger(env string) *slog.Logger {
 	var log *slog.Logger
 	fmt.Println(env)
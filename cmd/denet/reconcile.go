@@ -0,0 +1,66 @@
+package main
+
+import (
+	"denet/internal/config"
+	"denet/internal/jobs"
+	"denet/internal/storage/postgres"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+const reconcileUsage = `usage:
+  denet reconcile [--repair] [--report report.json]`
+
+// runReconcile implements the "denet reconcile" subcommand. Mismatches are
+// logged as JSON to stderr and the full report is written as JSON to stdout
+// or to the --report file.
+func runReconcile(args []string) int {
+	fs := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	repair := fs.Bool("repair", false, "post correction entries for mismatches")
+	reportFile := fs.String("report", "", "write the JSON report to this file instead of stdout")
+	fs.Usage = func() { fmt.Fprintln(os.Stderr, reconcileUsage) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	log := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+
+	cfg := config.MustLoad()
+	storage, err := postgres.New(cfg.StoragePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to init storage:", err)
+		return 1
+	}
+
+	report, err := storage.ReconcileBalances(*repair)
+	if report != nil {
+		jobs.LogReport(log, report)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to reconcile balances:", err)
+		return 1
+	}
+
+	var out io.Writer = os.Stdout
+	if *reportFile != "" {
+		f, err := os.Create(*reportFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"denet/internal/catalog"
+	"denet/internal/config"
+	"denet/internal/storage/postgres"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const tasksUsage = `usage:
+  denet tasks sync -f tasks.yaml [--dry-run]
+  denet tasks export [-f tasks.yaml]`
+
+// runTasks implements the "denet tasks" subcommand that keeps the task
+// catalog in sync with a YAML file.
+func runTasks(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, tasksUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "sync":
+		return runTasksSync(args[1:])
+	case "export":
+		return runTasksExport(args[1:])
+	}
+	fmt.Fprintln(os.Stderr, tasksUsage)
+	return 2
+}
+
+func runTasksSync(args []string) int {
+	fs := flag.NewFlagSet("tasks sync", flag.ContinueOnError)
+	file := fs.String("f", "", "catalog file to sync")
+	dryRun := fs.Bool("dry-run", false, "print the changes without applying them")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *file == "" {
+		fmt.Fprintln(os.Stderr, tasksUsage)
+		return 2
+	}
+
+	desired, err := catalog.Load(*file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	cfg := config.MustLoad()
+	storage, err := postgres.New(cfg.StoragePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to init storage:", err)
+		return 1
+	}
+
+	// Task rewards reference currencies, which must exist before the sync.
+	if err := storage.SyncCurrencies(currencies(cfg)); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to sync currencies:", err)
+		return 1
+	}
+
+	current, err := storage.ListCatalog()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to read catalog:", err)
+		return 1
+	}
+
+	changes := catalog.Diff(current, desired.Models())
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if *dryRun {
+		fmt.Println("dry run, nothing applied")
+		return 0
+	}
+
+	if err := storage.SyncCatalog(desired.Models()); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to sync catalog:", err)
+		return 1
+	}
+	fmt.Printf("synced %d tasks\n", len(desired.Tasks))
+	return 0
+}
+
+func runTasksExport(args []string) int {
+	fs := flag.NewFlagSet("tasks export", flag.ContinueOnError)
+	file := fs.String("f", "", "write the catalog to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := config.MustLoad()
+	storage, err := postgres.New(cfg.StoragePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to init storage:", err)
+		return 1
+	}
+
+	tasks, err := storage.ListCatalog()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to read catalog:", err)
+		return 1
+	}
+
+	var out io.Writer = os.Stdout
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := catalog.Export(out, tasks); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+// Package airdrop turns balance snapshots into Merkle airdrops claimable
+// through a MerkleDistributor contract.
+package airdrop
+
+import (
+	"denet/internal/lib/eth"
+	"denet/internal/lib/merkle"
+	"denet/internal/lib/models"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+var ErrNoClaims = errors.New("no snapshot entry has a linked wallet")
+
+// Build creates the claims of an airdrop from snapshot. Each user with a
+// wallet in wallets receives their snapshot balance scaled by 10^decimals;
+// users without one are returned in skipped. Claims are indexed in order of
+// account address, matching the usual MerkleDistributor tooling.
+func Build(snapshot *models.Snapshot, wallets map[int64]string, decimals int) (*models.Airdrop, []models.AirdropClaim, []models.SnapshotEntry, error) {
+	if decimals < 0 || decimals > 36 {
+		return nil, nil, nil, errors.New("decimals must be between 0 and 36")
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+
+	type candidate struct {
+		userID  int64
+		account eth.Address
+		amount  *big.Int
+	}
+	var candidates []candidate
+	var skipped []models.SnapshotEntry
+	for _, entry := range snapshot.Entries {
+		wallet, ok := wallets[entry.UserId]
+		if !ok {
+			skipped = append(skipped, entry)
+			continue
+		}
+		account, err := eth.ParseAddress(wallet)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("user %d: wallet %q: %w", entry.UserId, wallet, err)
+		}
+		amount := new(big.Int).Mul(big.NewInt(entry.Balance), scale)
+		candidates = append(candidates, candidate{userID: entry.UserId, account: account, amount: amount})
+	}
+	if len(candidates) == 0 {
+		return nil, nil, skipped, ErrNoClaims
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i].account.String(), candidates[j].account.String()
+		if a != b {
+			return a < b
+		}
+		return candidates[i].userID < candidates[j].userID
+	})
+
+	leaves := make([]merkle.Hash, len(candidates))
+	total := new(big.Int)
+	for i, c := range candidates {
+		leaves[i] = merkle.Leaf(uint64(i), c.account, c.amount)
+		total.Add(total, c.amount)
+	}
+	tree := merkle.New(leaves)
+	root := tree.Root()
+
+	claims := make([]models.AirdropClaim, len(candidates))
+	for i, c := range candidates {
+		proof, ok := tree.Proof(leaves[i])
+		if !ok || !merkle.Verify(root, leaves[i], proof) {
+			return nil, nil, nil, fmt.Errorf("user %d: proof does not verify", c.userID)
+		}
+		claims[i] = models.AirdropClaim{
+			UserId:  c.userID,
+			Index:   int64(i),
+			Account: c.account.String(),
+			Amount:  c.amount.String(),
+			Proof:   hexHashes(proof),
+			Root:    hexHash(root),
+		}
+	}
+
+	drop := &models.Airdrop{
+		SnapshotId: snapshot.Id,
+		Root:       hexHash(root),
+		Decimals:   decimals,
+		Claims:     int64(len(claims)),
+		Total:      total.String(),
+	}
+	return drop, claims, skipped, nil
+}
+
+func hexHash(h merkle.Hash) string {
+	return "0x" + hex.EncodeToString(h[:])
+}
+
+func hexHashes(hs []merkle.Hash) []string {
+	out := make([]string, len(hs))
+	for i, h := range hs {
+		out[i] = hexHash(h)
+	}
+	return out
+}
@@ -0,0 +1,140 @@
+package airdrop
+
+import (
+	"denet/internal/lib/eth"
+	"denet/internal/lib/merkle"
+	"denet/internal/lib/models"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func snapshot() *models.Snapshot {
+	return &models.Snapshot{
+		Id: 3,
+		Entries: []models.SnapshotEntry{
+			{Rank: 1, UserId: 10, Username: "alice", Balance: 300},
+			{Rank: 2, UserId: 11, Username: "bob", Balance: 250},
+			{Rank: 3, UserId: 12, Username: "carol", Balance: 200},
+			{Rank: 4, UserId: 13, Username: "dave", Balance: 100},
+		},
+	}
+}
+
+func decodeHash(t *testing.T, s string) merkle.Hash {
+	t.Helper()
+
+	var h merkle.Hash
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(b) != len(h) {
+		t.Fatalf("bad hash %q", s)
+	}
+	copy(h[:], b)
+	return h
+}
+
+func TestBuild(t *testing.T) {
+	wallets := map[int64]string{
+		10: "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		11: "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		13: "0x3c44cdddb6a900fa2b585dd299e03d12fa4293bc",
+	}
+
+	drop, claims, skipped, err := Build(snapshot(), wallets, 2)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	if len(skipped) != 1 || skipped[0].UserId != 12 {
+		t.Errorf("skipped = %+v, want only user 12", skipped)
+	}
+
+	// Claims are indexed by account address, not by rank.
+	want := []struct {
+		userID  int64
+		account string
+		amount  string
+	}{
+		{13, "0x3c44cdddb6a900fa2b585dd299e03d12fa4293bc", "10000"},
+		{11, "0x70997970c51812dc3a010c7d01b50e0d17dc79c8", "25000"},
+		{10, "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266", "30000"},
+	}
+	if len(claims) != len(want) {
+		t.Fatalf("got %d claims, want %d", len(claims), len(want))
+	}
+	root := decodeHash(t, drop.Root)
+	for i, c := range claims {
+		w := want[i]
+		if c.Index != int64(i) || c.UserId != w.userID || c.Account != w.account || c.Amount != w.amount {
+			t.Errorf("claim %d = %+v, want user %d account %s amount %s", i, c, w.userID, w.account, w.amount)
+		}
+		if c.Root != drop.Root {
+			t.Errorf("claim %d root = %s, want %s", i, c.Root, drop.Root)
+		}
+
+		account, err := eth.ParseAddress(c.Account)
+		if err != nil {
+			t.Fatalf("claim %d: %v", i, err)
+		}
+		amount, _ := new(big.Int).SetString(c.Amount, 10)
+		var proof []merkle.Hash
+		for _, p := range c.Proof {
+			proof = append(proof, decodeHash(t, p))
+		}
+		if !merkle.Verify(root, merkle.Leaf(uint64(c.Index), account, amount), proof) {
+			t.Errorf("claim %d: proof does not verify", i)
+		}
+	}
+
+	if drop.SnapshotId != 3 || drop.Decimals != 2 || drop.Claims != 3 || drop.Total != "65000" {
+		t.Errorf("airdrop = %+v", drop)
+	}
+}
+
+func TestBuildDecimals(t *testing.T) {
+	wallets := map[int64]string{10: "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"}
+
+	tests := []struct {
+		decimals int
+		amount   string
+		wantErr  bool
+	}{
+		{decimals: 0, amount: "300"},
+		{decimals: 18, amount: "300000000000000000000"},
+		{decimals: 36, amount: "300" + strings.Repeat("0", 36)},
+		{decimals: -1, wantErr: true},
+		{decimals: 37, wantErr: true},
+	}
+	for _, tt := range tests {
+		_, claims, _, err := Build(snapshot(), wallets, tt.decimals)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("decimals %d: want error", tt.decimals)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("decimals %d: %v", tt.decimals, err)
+		}
+		if claims[0].Amount != tt.amount {
+			t.Errorf("decimals %d: amount = %s, want %s", tt.decimals, claims[0].Amount, tt.amount)
+		}
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	_, _, skipped, err := Build(snapshot(), nil, 0)
+	if !errors.Is(err, ErrNoClaims) {
+		t.Errorf("no wallets: error = %v, want ErrNoClaims", err)
+	}
+	if len(skipped) != 4 {
+		t.Errorf("no wallets: %d skipped, want 4", len(skipped))
+	}
+
+	_, _, _, err = Build(snapshot(), map[int64]string{10: "0xnot-an-address"}, 0)
+	if !errors.Is(err, eth.ErrInvalidAddress) {
+		t.Errorf("bad wallet: error = %v, want ErrInvalidAddress", err)
+	}
+}
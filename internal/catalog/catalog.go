@@ -0,0 +1,308 @@
+package catalog
+
+import (
+	"denet/internal/lib/models"
+	"denet/internal/lib/recurrence"
+	"denet/internal/verifier"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+	"gopkg.in/yaml.v3"
+)
+
+// File is the version-controlled task catalog.
+type File struct {
+	Tasks []Task `yaml:"tasks"`
+}
+
+// Task describes a catalog task in YAML. Omitted fields take the same
+// defaults as tasks created through the admin API.
+type Task struct {
+	Slug            string     `yaml:"slug"`
+	Title           string     `yaml:"title"`
+	Type            string     `yaml:"type,omitempty"`
+	Target          string     `yaml:"target,omitempty"`
+	Reward          int64      `yaml:"reward"`
+	Active          *bool      `yaml:"active,omitempty"`
+	Recurrence      string     `yaml:"recurrence,omitempty"`
+	CooldownSeconds int64      `yaml:"cooldown_seconds,omitempty"`
+	StreakBonus     int64      `yaml:"streak_bonus,omitempty"`
+	StartsAt        *time.Time `yaml:"starts_at,omitempty"`
+	EndsAt          *time.Time `yaml:"ends_at,omitempty"`
+	MaxCompletions  int64      `yaml:"max_completions,omitempty"`
+	Counter         string     `yaml:"counter,omitempty"`
+	TargetValue     int64      `yaml:"target_value,omitempty"`
+	ExpiryDays      int64      `yaml:"expiry_days,omitempty"`
+	Prerequisites   []string   `yaml:"prerequisites,omitempty"`
+	// Rewards grants currencies other than points, keyed by currency code.
+	Rewards map[string]int64 `yaml:"rewards,omitempty"`
+}
+
+// Load reads and validates a catalog file.
+func Load(path string) (*File, error) {
+	var file File
+	if err := cleanenv.ReadConfig(path, &file); err != nil {
+		return nil, fmt.Errorf("read catalog: %w", err)
+	}
+	if err := file.Validate(); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+func (f *File) Validate() error {
+	seen := make(map[string]bool, len(f.Tasks))
+	for i, task := range f.Tasks {
+		if task.Slug == "" {
+			return fmt.Errorf("task #%d: slug is required", i+1)
+		}
+		if seen[task.Slug] {
+			return fmt.Errorf("task %q: duplicate slug", task.Slug)
+		}
+		seen[task.Slug] = true
+
+		model := task.Model()
+		switch {
+		case task.Title == "":
+			return fmt.Errorf("task %q: title is required", task.Slug)
+		case task.Reward < 0 || task.CooldownSeconds < 0 || task.StreakBonus < 0 || task.MaxCompletions < 0 || task.TargetValue < 0 ||
+			task.ExpiryDays < 0:
+			return fmt.Errorf("task %q: numeric fields must not be negative", task.Slug)
+		case verifier.NeedsTarget(task.Type) && task.Target == "":
+			return fmt.Errorf("task %q: %s tasks need a target", task.Slug, task.Type)
+		case !model.Policy().Valid():
+			return fmt.Errorf("task %q: invalid recurrence %q", task.Slug, model.Recurrence)
+		case (task.Counter == "") != (task.TargetValue == 0):
+			return fmt.Errorf("task %q: counter and target_value must be set together", task.Slug)
+		case model.ProgressBased() && model.Recurrence != recurrence.Once:
+			return fmt.Errorf("task %q: progress-based tasks cannot recur", task.Slug)
+		case task.StartsAt != nil && task.EndsAt != nil && !task.StartsAt.Before(*task.EndsAt):
+			return fmt.Errorf("task %q: starts_at must be before ends_at", task.Slug)
+		case slices.Contains(task.Prerequisites, task.Slug):
+			return fmt.Errorf("task %q: depends on itself", task.Slug)
+		}
+		for currency, amount := range task.Rewards {
+			if currency == models.CurrencyPoints {
+				return fmt.Errorf("task %q: points are granted with reward, not rewards", task.Slug)
+			}
+			if amount <= 0 {
+				return fmt.Errorf("task %q: reward in %s must be positive", task.Slug, currency)
+			}
+		}
+	}
+	return nil
+}
+
+// Model converts the YAML description into a catalog task.
+func (t Task) Model() models.CatalogTask {
+	task := models.CatalogTask{
+		Task: models.Task{
+			Slug:            t.Slug,
+			Title:           t.Title,
+			Type:            t.Type,
+			Target:          t.Target,
+			Reward:          t.Reward,
+			Active:          t.Active == nil || *t.Active,
+			Recurrence:      t.Recurrence,
+			CooldownSeconds: t.CooldownSeconds,
+			StreakBonus:     t.StreakBonus,
+			StartsAt:        utc(t.StartsAt),
+			EndsAt:          utc(t.EndsAt),
+			MaxCompletions:  t.MaxCompletions,
+			Counter:         t.Counter,
+			TargetValue:     t.TargetValue,
+			ExpiryDays:      t.ExpiryDays,
+			Rewards:         t.Rewards,
+		},
+		Prerequisites: t.Prerequisites,
+	}
+	if task.Type == "" {
+		task.Type = verifier.TypeNone
+	}
+	if task.Recurrence == "" {
+		task.Recurrence = recurrence.Once
+	}
+	return task
+}
+
+func (f *File) Models() []models.CatalogTask {
+	tasks := make([]models.CatalogTask, 0, len(f.Tasks))
+	for _, task := range f.Tasks {
+		tasks = append(tasks, task.Model())
+	}
+	return tasks
+}
+
+// FromModels converts catalog tasks into their YAML description, leaving out
+// fields that hold defaults.
+func FromModels(tasks []models.CatalogTask) *File {
+	file := &File{Tasks: make([]Task, 0, len(tasks))}
+	for _, task := range tasks {
+		t := Task{
+			Slug:            task.Slug,
+			Title:           task.Title,
+			Target:          task.Target,
+			Reward:          task.Reward,
+			CooldownSeconds: task.CooldownSeconds,
+			StreakBonus:     task.StreakBonus,
+			StartsAt:        utc(task.StartsAt),
+			EndsAt:          utc(task.EndsAt),
+			MaxCompletions:  task.MaxCompletions,
+			Counter:         task.Counter,
+			TargetValue:     task.TargetValue,
+			ExpiryDays:      task.ExpiryDays,
+			Rewards:         task.Rewards,
+			Prerequisites:   task.Prerequisites,
+		}
+		if task.Type != verifier.TypeNone {
+			t.Type = task.Type
+		}
+		if task.Recurrence != recurrence.Once {
+			t.Recurrence = task.Recurrence
+		}
+		if !task.Active {
+			active := false
+			t.Active = &active
+		}
+		file.Tasks = append(file.Tasks, t)
+	}
+	return file
+}
+
+// Export writes the catalog as YAML.
+func Export(w io.Writer, tasks []models.CatalogTask) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(FromModels(tasks)); err != nil {
+		return fmt.Errorf("encode catalog: %w", err)
+	}
+	return enc.Close()
+}
+
+const (
+	ChangeCreate    = "create"
+	ChangeUpdate    = "update"
+	ChangeUnchanged = "unchanged"
+	ChangeUntracked = "untracked"
+)
+
+// Change describes what syncing the catalog does to a single task.
+type Change struct {
+	Slug   string
+	Kind   string
+	Fields []string
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeCreate:
+		return "+ " + c.Slug
+	case ChangeUpdate:
+		return "~ " + c.Slug + ": " + strings.Join(c.Fields, ", ")
+	case ChangeUntracked:
+		return "? " + c.Slug + " (not in file, left untouched)"
+	}
+	return "  " + c.Slug
+}
+
+// Diff compares the current catalog with the desired one.
+func Diff(current []models.CatalogTask, desired []models.CatalogTask) []Change {
+	bySlug := make(map[string]models.CatalogTask, len(current))
+	for _, task := range current {
+		bySlug[task.Slug] = task
+	}
+
+	var changes []Change
+	inFile := make(map[string]bool, len(desired))
+	for _, task := range desired {
+		inFile[task.Slug] = true
+		existing, ok := bySlug[task.Slug]
+		if !ok {
+			changes = append(changes, Change{Slug: task.Slug, Kind: ChangeCreate})
+			continue
+		}
+
+		fields := diffFields(existing, task)
+		kind := ChangeUpdate
+		if len(fields) == 0 {
+			kind = ChangeUnchanged
+		}
+		changes = append(changes, Change{Slug: task.Slug, Kind: kind, Fields: fields})
+	}
+
+	for _, task := range current {
+		if !inFile[task.Slug] {
+			changes = append(changes, Change{Slug: task.Slug, Kind: ChangeUntracked})
+		}
+	}
+	return changes
+}
+
+func diffFields(from, to models.CatalogTask) []string {
+	pairs := []struct {
+		name     string
+		from, to any
+	}{
+		{"title", from.Title, to.Title},
+		{"type", from.Type, to.Type},
+		{"target", from.Target, to.Target},
+		{"reward", from.Reward, to.Reward},
+		{"active", from.Active, to.Active},
+		{"recurrence", from.Recurrence, to.Recurrence},
+		{"cooldown_seconds", from.CooldownSeconds, to.CooldownSeconds},
+		{"streak_bonus", from.StreakBonus, to.StreakBonus},
+		{"starts_at", timeValue(from.StartsAt), timeValue(to.StartsAt)},
+		{"ends_at", timeValue(from.EndsAt), timeValue(to.EndsAt)},
+		{"max_completions", from.MaxCompletions, to.MaxCompletions},
+		{"counter", from.Counter, to.Counter},
+		{"target_value", from.TargetValue, to.TargetValue},
+		{"expiry_days", from.ExpiryDays, to.ExpiryDays},
+		{"rewards", from.Rewards, to.Rewards},
+		{"prerequisites", sorted(from.Prerequisites), sorted(to.Prerequisites)},
+	}
+
+	var fields []string
+	for _, p := range pairs {
+		from, to := formatValue(p.from), formatValue(p.to)
+		if from != to {
+			fields = append(fields, fmt.Sprintf("%s %s -> %s", p.name, from, to))
+		}
+	}
+	return fields
+}
+
+func formatValue(v any) string {
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprint(v)
+}
+
+// timeValue formats an optional timestamp for diff output.
+func timeValue(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func sorted(slugs []string) []string {
+	slugs = slices.Clone(slugs)
+	slices.Sort(slugs)
+	if slugs == nil {
+		return []string{}
+	}
+	return slugs
+}
+
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
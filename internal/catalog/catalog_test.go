@@ -0,0 +1,176 @@
+package catalog
+
+import (
+	"denet/internal/lib/models"
+	"denet/internal/lib/recurrence"
+	"denet/internal/verifier"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name    string
+		tasks   []Task
+		wantErr string
+	}{
+		{name: "empty"},
+		{
+			name: "valid",
+			tasks: []Task{
+				{Slug: "join", Title: "Join", Reward: 10},
+				{Slug: "daily", Title: "Daily", Reward: 5, Recurrence: recurrence.Daily, StreakBonus: 1, Prerequisites: []string{"join"}},
+				{Slug: "follow", Title: "Follow", Type: verifier.TypeTwitterFollow, Target: "denet", Reward: 20},
+				{Slug: "invite", Title: "Invite", Counter: models.CounterReferrals, TargetValue: 3, Rewards: map[string]int64{"gems": 2}},
+				{Slug: "launch", Title: "Launch", StartsAt: &start, EndsAt: &end},
+			},
+		},
+		{
+			name:    "missing slug",
+			tasks:   []Task{{Slug: "join", Title: "Join"}, {Title: "No slug"}},
+			wantErr: "task #2: slug is required",
+		},
+		{
+			name:    "duplicate slug",
+			tasks:   []Task{{Slug: "join", Title: "Join"}, {Slug: "join", Title: "Again"}},
+			wantErr: `task "join": duplicate slug`,
+		},
+		{
+			name:    "missing title",
+			tasks:   []Task{{Slug: "join"}},
+			wantErr: `task "join": title is required`,
+		},
+		{
+			name:    "negative reward",
+			tasks:   []Task{{Slug: "join", Title: "Join", Reward: -1}},
+			wantErr: `task "join": numeric fields must not be negative`,
+		},
+		{
+			name:    "negative expiry",
+			tasks:   []Task{{Slug: "join", Title: "Join", ExpiryDays: -1}},
+			wantErr: `task "join": numeric fields must not be negative`,
+		},
+		{
+			name:    "missing target",
+			tasks:   []Task{{Slug: "like", Title: "Like", Type: verifier.TypeTwitterLike}},
+			wantErr: `task "like": twitter_like tasks need a target`,
+		},
+		{
+			name:    "unknown recurrence",
+			tasks:   []Task{{Slug: "join", Title: "Join", Recurrence: "hourly"}},
+			wantErr: `task "join": invalid recurrence "hourly"`,
+		},
+		{
+			name:    "cooldown without duration",
+			tasks:   []Task{{Slug: "join", Title: "Join", Recurrence: recurrence.Cooldown}},
+			wantErr: `task "join": invalid recurrence "cooldown"`,
+		},
+		{
+			name:    "counter without target value",
+			tasks:   []Task{{Slug: "invite", Title: "Invite", Counter: models.CounterReferrals}},
+			wantErr: `task "invite": counter and target_value must be set together`,
+		},
+		{
+			name:    "recurring progress task",
+			tasks:   []Task{{Slug: "invite", Title: "Invite", Counter: models.CounterCheckIns, TargetValue: 7, Recurrence: recurrence.Weekly}},
+			wantErr: `task "invite": progress-based tasks cannot recur`,
+		},
+		{
+			name:    "empty window",
+			tasks:   []Task{{Slug: "launch", Title: "Launch", StartsAt: &end, EndsAt: &start}},
+			wantErr: `task "launch": starts_at must be before ends_at`,
+		},
+		{
+			name:    "self prerequisite",
+			tasks:   []Task{{Slug: "join", Title: "Join", Prerequisites: []string{"join"}}},
+			wantErr: `task "join": depends on itself`,
+		},
+		{
+			name:    "points in rewards",
+			tasks:   []Task{{Slug: "join", Title: "Join", Rewards: map[string]int64{models.CurrencyPoints: 5}}},
+			wantErr: `task "join": points are granted with reward, not rewards`,
+		},
+		{
+			name:    "non-positive currency reward",
+			tasks:   []Task{{Slug: "join", Title: "Join", Rewards: map[string]int64{"gems": 0}}},
+			wantErr: `task "join": reward in gems must be positive`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&File{Tasks: tt.tasks}).Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	inactive := false
+
+	current := (&File{Tasks: []Task{
+		{Slug: "join", Title: "Join", Reward: 10},
+		{Slug: "daily", Title: "Daily", Reward: 5, Recurrence: recurrence.Daily, Prerequisites: []string{"join", "follow"}},
+		{Slug: "follow", Title: "Follow", Reward: 20},
+		{Slug: "legacy", Title: "Legacy", Reward: 1},
+	}}).Models()
+	desired := (&File{Tasks: []Task{
+		{Slug: "join", Title: "Join", Reward: 10},
+		{Slug: "daily", Title: "Daily check-in", Reward: 7, Recurrence: recurrence.Daily, Prerequisites: []string{"follow", "join"}},
+		{Slug: "follow", Title: "Follow", Reward: 20, Active: &inactive, StartsAt: &start},
+		{Slug: "invite", Title: "Invite", Counter: models.CounterReferrals, TargetValue: 3},
+	}}).Models()
+
+	want := []Change{
+		{Slug: "join", Kind: ChangeUnchanged},
+		{Slug: "daily", Kind: ChangeUpdate, Fields: []string{`title "Daily" -> "Daily check-in"`, "reward 5 -> 7"}},
+		{Slug: "follow", Kind: ChangeUpdate, Fields: []string{"active true -> false", `starts_at "-" -> "2026-03-01T00:00:00Z"`}},
+		{Slug: "invite", Kind: ChangeCreate},
+		{Slug: "legacy", Kind: ChangeUntracked},
+	}
+	got := Diff(current, desired)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Diff =\n%#v\nwant\n%#v", got, want)
+	}
+
+	lines := []string{
+		"  join",
+		`~ daily: title "Daily" -> "Daily check-in", reward 5 -> 7`,
+		`~ follow: active true -> false, starts_at "-" -> "2026-03-01T00:00:00Z"`,
+		"+ invite",
+		"? legacy (not in file, left untouched)",
+	}
+	for i, c := range got {
+		if c.String() != lines[i] {
+			t.Errorf("change %d = %q, want %q", i, c.String(), lines[i])
+		}
+	}
+}
+
+func TestDiffRoundTrip(t *testing.T) {
+	// A catalog exported from the database must sync back without changes.
+	tasks := (&File{Tasks: []Task{
+		{Slug: "join", Title: "Join", Reward: 10},
+		{Slug: "follow", Title: "Follow", Type: verifier.TypeTwitterFollow, Target: "denet", Reward: 20, Prerequisites: []string{"join"}},
+		{Slug: "weekly", Title: "Weekly", Recurrence: recurrence.Weekly, Rewards: map[string]int64{"gems": 1}},
+	}}).Models()
+	tasks[2].Active = false
+
+	for _, c := range Diff(tasks, FromModels(tasks).Models()) {
+		if c.Kind != ChangeUnchanged {
+			t.Errorf("%s", c)
+		}
+	}
+}
@@ -12,6 +12,12 @@ type Config struct {
 	Env         string `yaml:"env" env-default:"local"` //env-default:"develoment"
 	StoragePath string `yaml:"storage_path" env-required:"true"`
 	HTTPServer  `yaml:"http_server"`
+	Telegram    Telegram   `yaml:"telegram"`
+	Twitter     Twitter    `yaml:"twitter"`
+	Transfers   Transfers  `yaml:"transfers"`
+	Jobs        Jobs       `yaml:"jobs"`
+	Currencies  []Currency `yaml:"currencies"`
+	Boosts      []Boost    `yaml:"boosts"`
 }
 
 type HTTPServer struct {
@@ -22,6 +28,53 @@ type HTTPServer struct {
 	Password    string        `yaml:"password" env-required:"true" env:"HTTP_SERVER_PASSWORD"`
 }
 
+type Telegram struct {
+	BotToken string        `yaml:"bot_token" env:"TELEGRAM_BOT_TOKEN"`
+	Channel  string        `yaml:"channel"`
+	APIURL   string        `yaml:"api_url" env-default:"https://api.telegram.org"`
+	Timeout  time.Duration `yaml:"timeout" env-default:"5s"`
+}
+
+type Twitter struct {
+	BearerToken string        `yaml:"bearer_token" env:"TWITTER_BEARER_TOKEN"`
+	APIURL      string        `yaml:"api_url" env-default:"https://api.twitter.com"`
+	Timeout     time.Duration `yaml:"timeout" env-default:"5s"`
+	CacheTTL    time.Duration `yaml:"cache_ttl" env-default:"5m"`
+	MaxPages    int           `yaml:"max_pages" env-default:"5"`
+}
+
+// Transfers limits how many points a user may gift per UTC day. Zero
+// disables a limit.
+type Transfers struct {
+	DailyAmount int64 `yaml:"daily_amount" env-default:"1000"`
+	DailyCount  int64 `yaml:"daily_count" env-default:"10"`
+}
+
+// Currency is a named balance type. Points are always available and are the
+// only spendable currency; the others only accumulate.
+type Currency struct {
+	Code  string `yaml:"code"`
+	Title string `yaml:"title"`
+}
+
+// Boost multiplies task rewards in points. Empty Tasks or Tiers match every
+// task or user tier, and StartsAt/EndsAt bound an optional time window.
+type Boost struct {
+	Name       string     `yaml:"name"`
+	Multiplier float64    `yaml:"multiplier"`
+	StartsAt   *time.Time `yaml:"starts_at"`
+	EndsAt     *time.Time `yaml:"ends_at"`
+	Tasks      []string   `yaml:"tasks"`
+	Tiers      []string   `yaml:"tiers"`
+}
+
+// Jobs configures how often background jobs run. Zero disables a job.
+type Jobs struct {
+	ExpiryInterval    time.Duration `yaml:"expiry_interval" env-default:"1h"`
+	ReconcileInterval time.Duration `yaml:"reconcile_interval" env-default:"0"`
+	ReconcileRepair   bool          `yaml:"reconcile_repair"`
+}
+
 func MustLoad() *Config {
 	os.Setenv("CONFIG_PATH", "D:\\GoModules\\DeNet\\config\\local.yaml")
 	configPath := os.Getenv("CONFIG_PATH")
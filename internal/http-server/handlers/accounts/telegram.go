@@ -0,0 +1,87 @@
+package accounts
+
+import (
+	"denet/internal/config"
+	"denet/internal/http-server/handlers/owner"
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"denet/internal/verifier/telegram"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// loginMaxAge is how old a Telegram Login Widget payload may be.
+const loginMaxAge = 24 * time.Hour
+
+type TelegramLinker interface {
+	GetUSER(id int64) (*models.User, error)
+	LinkTelegram(userID int64, telegramID int64) error
+}
+
+// NewLinkTelegram links the Telegram account of a Login Widget payload to
+// the user, which telegram_subscription tasks need for verification.
+func NewLinkTelegram(log *slog.Logger, linker TelegramLinker, cfg config.Telegram) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.accounts.LinkTelegram"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		ids := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(ids, 10, 64)
+		if err != nil {
+			log.Error("invalid id format", slog.String("id", ids))
+			render.JSON(w, r, response.Error("invalid id format"))
+			return
+		}
+
+		if _, ok := owner.Check(w, r, log, linker, id, "cannot link an account to another user"); !ok {
+			return
+		}
+
+		var login telegram.Login
+		err = render.DecodeJSON(r.Body, &login)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.JSON(w, r, response.Error("failed to decode request: "+err.Error()))
+			return
+		}
+
+		err = telegram.CheckLogin(cfg.BotToken, login, time.Now(), loginMaxAge)
+		if errors.Is(err, telegram.ErrLoginExpired) {
+			log.Info("telegram login expired", slog.Int64("id", id))
+			render.JSON(w, r, response.Error("telegram login has expired, log in again"))
+			return
+		}
+		if err != nil {
+			log.Info("invalid telegram login", slog.Int64("id", id), sl.Err(err))
+			render.JSON(w, r, response.Error("invalid telegram login"))
+			return
+		}
+
+		err = linker.LinkTelegram(id, login.Id)
+		if errors.Is(err, storage.ErrAccountTaken) {
+			log.Info("telegram account linked to another user", slog.Int64("id", id))
+			render.JSON(w, r, response.Error("telegram account is linked to another user"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to link telegram account", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		log.Info("telegram account linked", slog.Int64("id", id), slog.Int64("telegram_id", login.Id))
+
+		render.JSON(w, r, response.OK())
+	}
+}
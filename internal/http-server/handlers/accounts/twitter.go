@@ -0,0 +1,83 @@
+package accounts
+
+import (
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/storage"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+// TwitterRequest carries the numeric X account ID, not the handle, since
+// the follower lists checked by the verifier only contain IDs.
+type TwitterRequest struct {
+	TwitterId string `json:"twitterId" validate:"required,numeric,max=64"`
+}
+
+type TwitterLinker interface {
+	LinkTwitter(userID int64, twitterID string) error
+}
+
+// NewLinkTwitter lets an admin link the X account a user proved to own,
+// which twitter_* tasks need for verification.
+func NewLinkTwitter(log *slog.Logger, linker TwitterLinker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.users.accounts.LinkTwitter"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		ids := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(ids, 10, 64)
+		if err != nil {
+			log.Error("invalid id format", slog.String("id", ids))
+			render.JSON(w, r, response.Error("invalid id format"))
+			return
+		}
+
+		var req TwitterRequest
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.JSON(w, r, response.Error("failed to decode request: "+err.Error()))
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, response.ValidationError(validateErr))
+			return
+		}
+
+		err = linker.LinkTwitter(id, req.TwitterId)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.Int64("id", id))
+			render.JSON(w, r, response.Error("user not found"))
+			return
+		}
+		if errors.Is(err, storage.ErrAccountTaken) {
+			log.Info("x account linked to another user", slog.Int64("id", id))
+			render.JSON(w, r, response.Error("x account is linked to another user"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to link x account", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		log.Info("x account linked", slog.Int64("id", id), slog.String("twitter_id", req.TwitterId))
+
+		render.JSON(w, r, response.OK())
+	}
+}
@@ -0,0 +1,76 @@
+package airdrop
+
+import (
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	response.Response
+	Claim *models.AirdropClaim `json:"claim,omitempty"`
+}
+
+type ClaimGetter interface {
+	GetAirdropClaim(userID int64, airdropID int64) (*models.AirdropClaim, error)
+}
+
+// NewProof returns the user's claim with its Merkle proof in the latest
+// airdrop, or in the one selected with ?airdrop=.
+func NewProof(log *slog.Logger, getter ClaimGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.airdrop.Proof"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		ids := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(ids, 10, 64)
+		if err != nil {
+			log.Error("invalid id format", slog.String("id", ids))
+			render.JSON(w, r, response.Error("invalid id format"))
+			return
+		}
+
+		var airdropID int64
+		if v := r.URL.Query().Get("airdrop"); v != "" {
+			airdropID, err = strconv.ParseInt(v, 10, 64)
+			if err != nil || airdropID <= 0 {
+				log.Info("invalid airdrop id", slog.String("airdrop", v))
+				render.JSON(w, r, response.Error("invalid airdrop id"))
+				return
+			}
+		}
+
+		claim, err := getter.GetAirdropClaim(id, airdropID)
+		if errors.Is(err, storage.ErrAirdropNotFound) {
+			log.Info("airdrop not found", slog.Int64("airdrop", airdropID))
+			render.JSON(w, r, response.Error("airdrop not found"))
+			return
+		}
+		if errors.Is(err, storage.ErrClaimNotFound) {
+			log.Info("no claim", slog.Int64("id", id), slog.Int64("airdrop", airdropID))
+			render.JSON(w, r, response.Error("user has no claim in the airdrop"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to get airdrop claim", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Claim:    claim,
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package balance
+
+import (
+	"denet/internal/http-server/handlers/owner"
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	response.Response
+	UserId   int64     `json:"user_id"`
+	Currency string    `json:"currency"`
+	At       time.Time `json:"at"`
+	Balance  int64     `json:"balance"`
+}
+
+type BalanceAt interface {
+	GetUSER(id int64) (*models.User, error)
+	GetBalanceAt(userID int64, currency string, at time.Time) (int64, error)
+}
+
+// NewBalance returns the user's balance as of the RFC 3339 time in ?at=,
+// or now when it is omitted. ?currency= defaults to points.
+func NewBalance(log *slog.Logger, balanceAt BalanceAt) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.balance.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		ids := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(ids, 10, 64)
+		if err != nil {
+			log.Error("invalid id format", slog.String("id", ids))
+			render.JSON(w, r, response.Error("invalid id format"))
+			return
+		}
+
+		if _, ok := owner.Check(w, r, log, balanceAt, id, "cannot view another user's balance"); !ok {
+			return
+		}
+
+		query := r.URL.Query()
+		currency := query.Get("currency")
+		if currency == "" {
+			currency = models.CurrencyPoints
+		}
+
+		at := time.Now().UTC()
+		if v := query.Get("at"); v != "" {
+			at, err = time.Parse(time.RFC3339, v)
+			if err != nil {
+				log.Info("invalid at", slog.String("at", v))
+				render.JSON(w, r, response.Error("at must be an RFC 3339 timestamp"))
+				return
+			}
+			at = at.UTC()
+		}
+
+		balance, err := balanceAt.GetBalanceAt(id, currency, at)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.Int64("id", id))
+			render.JSON(w, r, response.Error("user not found"))
+			return
+		}
+		if errors.Is(err, storage.ErrUnknownCurrency) {
+			log.Info("unknown currency", slog.String("currency", currency))
+			render.JSON(w, r, response.Error("unknown currency"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to get balance", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			UserId:   id,
+			Currency: currency,
+			At:       at,
+			Balance:  balance,
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package boosts
+
+import (
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/boost"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	response.Response
+	Boosts []boost.Rule `json:"boosts"`
+}
+
+type ActiveBoosts interface {
+	Active(now time.Time) []boost.Rule
+}
+
+// NewList returns the boosts active right now so that clients can advertise
+// them.
+func NewList(log *slog.Logger, boosts ActiveBoosts) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.boosts.List"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		active := boosts.Active(time.Now().UTC())
+		log.Debug("active boosts", slog.Int("count", len(active)))
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Boosts:   active,
+		})
+	}
+}
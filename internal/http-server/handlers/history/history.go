@@ -0,0 +1,165 @@
+package history
+
+import (
+	"denet/internal/http-server/handlers/owner"
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+var reasons = map[string]bool{
+	models.ReasonOpening:    true,
+	models.ReasonSignup:     true,
+	models.ReasonTask:       true,
+	models.ReasonReferral:   true,
+	models.ReasonAdmin:      true,
+	models.ReasonTransfer:   true,
+	models.ReasonRedemption: true,
+	models.ReasonExpiry:     true,
+	models.ReasonCorrection: true,
+}
+
+type Response struct {
+	response.Response
+	Entries    []models.HistoryEntry `json:"entries"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+type USERHistory interface {
+	GetUSER(id int64) (*models.User, error)
+	GetHistory(userID int64, filter models.HistoryFilter) ([]models.HistoryEntry, error)
+}
+
+func NewHistory(log *slog.Logger, uSERHistory USERHistory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.history.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("Request received", slog.String("users", r.URL.String()))
+		ids := chi.URLParam(r, "id")
+		if ids == "" {
+			log.Info("id is empty")
+			render.JSON(w, r, response.Error("invalid request"))
+			return
+		}
+
+		id, err := strconv.ParseInt(ids, 10, 64)
+		if err != nil {
+			log.Error("invalid id format", slog.String("id", ids))
+			render.JSON(w, r, response.Error("invalid id format"))
+			return
+		}
+
+		if _, ok := owner.Check(w, r, log, uSERHistory, id, "cannot view another user's history"); !ok {
+			return
+		}
+
+		filter, err := parseFilter(r)
+		if err != nil {
+			log.Info("invalid filter", sl.Err(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		// One extra entry tells whether there is a next page.
+		limit := filter.Limit
+		filter.Limit++
+
+		entries, err := uSERHistory.GetHistory(id, filter)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", "id", ids)
+			render.JSON(w, r, response.Error("user not found"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to get history", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		res := Response{
+			Response: response.OK(),
+			Entries:  entries,
+		}
+		if len(entries) > limit {
+			res.Entries = entries[:limit]
+			res.NextCursor = strconv.FormatInt(entries[limit-1].Id, 10)
+		}
+		if res.Entries == nil {
+			res.Entries = []models.HistoryEntry{}
+		}
+		render.JSON(w, r, res)
+	}
+}
+
+func parseFilter(r *http.Request) (models.HistoryFilter, error) {
+	query := r.URL.Query()
+	filter := models.HistoryFilter{
+		Limit:    defaultLimit,
+		Reason:   query.Get("reason"),
+		Currency: query.Get("currency"),
+	}
+
+	if filter.Reason != "" && !reasons[filter.Reason] {
+		return filter, errors.New("invalid reason")
+	}
+
+	if v := query.Get("cursor"); v != "" {
+		cursor, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || cursor <= 0 {
+			return filter, errors.New("invalid cursor")
+		}
+		filter.Cursor = cursor
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxLimit {
+			return filter, errors.New("limit must be between 1 and " + strconv.Itoa(maxLimit))
+		}
+		filter.Limit = limit
+	}
+
+	var err error
+	if filter.From, err = parseTime(query.Get("from")); err != nil {
+		return filter, errors.New("invalid from date")
+	}
+	if filter.To, err = parseTime(query.Get("to")); err != nil {
+		return filter, errors.New("invalid to date")
+	}
+	return filter, nil
+}
+
+// parseTime accepts RFC 3339 timestamps and plain dates, which mean midnight
+// UTC.
+func parseTime(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	t = t.UTC()
+	return &t, nil
+}
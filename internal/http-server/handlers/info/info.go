@@ -17,14 +17,43 @@ import (
 
 type USERInfo interface {
 	GetUSER(id int64) (*models.User, error)
+	GetUserStreak(id int64) (int64, error)
+	GetUserSubmissions(id int64) ([]models.Submission, error)
+	GetUserProgress(id int64) ([]models.Progress, error)
+	GetExpiringPoints(id int64, before time.Time) (int64, error)
+	GetUserBalances(id int64) (map[string]int64, error)
 }
 
+// expiryWindow is how far ahead the status looks for expiring points.
+const expiryWindow = 7 * 24 * time.Hour
+
 type Response struct {
 	response.Response
-	Username    string    `json:"username,omitempty"`
-	Points      int64     `json:"points,omitempty"`
-	Referral_id int64     `json:"referral_id"`
-	Created_at  time.Time `json:"created_at"`
+	Username    string           `json:"username,omitempty"`
+	Points      int64            `json:"points,omitempty"`
+	Balances    map[string]int64 `json:"balances"`
+	Referral_id int64            `json:"referral_id"`
+	Created_at  time.Time        `json:"created_at"`
+	Wallet      string           `json:"wallet_address,omitempty"`
+	Streak      int64            `json:"streak"`
+	Expiring    int64            `json:"expiring_points"`
+	Submissions []SubmissionData `json:"submissions,omitempty"`
+	Progress    []ProgressData   `json:"progress,omitempty"`
+}
+
+type ProgressData struct {
+	Task      string `json:"task"`
+	Current   int64  `json:"current"`
+	Target    int64  `json:"target"`
+	Completed bool   `json:"completed"`
+}
+
+type SubmissionData struct {
+	Id        int64     `json:"id"`
+	Task      string    `json:"task"`
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 func NewUserInfo(log *slog.Logger, uSERInfo USERInfo) http.HandlerFunc {
@@ -63,14 +92,86 @@ func NewUserInfo(log *slog.Logger, uSERInfo USERInfo) http.HandlerFunc {
 			return
 		}
 
+		streak, err := uSERInfo.GetUserStreak(id)
+		if err != nil {
+			log.Error("failed to get user streak", sl.Err(err))
+
+			render.JSON(w, r, response.Error("internal error"))
+
+			return
+		}
+
+		userSubmissions, err := uSERInfo.GetUserSubmissions(id)
+		if err != nil {
+			log.Error("failed to get user submissions", sl.Err(err))
+
+			render.JSON(w, r, response.Error("internal error"))
+
+			return
+		}
+
+		var submissions []SubmissionData
+		for _, sub := range userSubmissions {
+			submissions = append(submissions, SubmissionData{
+				Id:        sub.Id,
+				Task:      sub.TaskSlug,
+				Status:    sub.Status,
+				Reason:    sub.Reason,
+				CreatedAt: sub.CreatedAt,
+			})
+		}
+
+		userProgress, err := uSERInfo.GetUserProgress(id)
+		if err != nil {
+			log.Error("failed to get user progress", sl.Err(err))
+
+			render.JSON(w, r, response.Error("internal error"))
+
+			return
+		}
+
+		var progress []ProgressData
+		for _, p := range userProgress {
+			progress = append(progress, ProgressData{
+				Task:      p.TaskSlug,
+				Current:   p.Current,
+				Target:    p.Target,
+				Completed: p.CompletedAt != nil,
+			})
+		}
+
+		balances, err := uSERInfo.GetUserBalances(id)
+		if err != nil {
+			log.Error("failed to get user balances", sl.Err(err))
+
+			render.JSON(w, r, response.Error("internal error"))
+
+			return
+		}
+
+		expiring, err := uSERInfo.GetExpiringPoints(id, time.Now().UTC().Add(expiryWindow))
+		if err != nil {
+			log.Error("failed to get expiring points", sl.Err(err))
+
+			render.JSON(w, r, response.Error("internal error"))
+
+			return
+		}
+
 		log.Info("got user", slog.String("user", resUSER.Username))
 
 		render.JSON(w, r, Response{
 			Response:    response.OK(),
 			Username:    resUSER.Username,
 			Points:      resUSER.Points,
+			Balances:    balances,
 			Referral_id: resUSER.Referral_id,
 			Created_at:  resUSER.Created_at,
+			Wallet:      resUSER.Wallet,
+			Streak:      streak,
+			Expiring:    expiring,
+			Submissions: submissions,
+			Progress:    progress,
 		})
 	}
 }
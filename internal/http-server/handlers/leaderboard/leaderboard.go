@@ -4,6 +4,8 @@ import (
 	"denet/internal/lib/api/response"
 	"denet/internal/lib/logger/sl"
 	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
 	"log/slog"
 	"net/http"
 	"time"
@@ -13,18 +15,20 @@ import (
 
 type Response struct {
 	Response response.Response `json:"response"`
+	Currency string            `json:"currency"`
 	Users    []UserData        `json:"users"`
 }
 
 type UserData struct {
 	Username    string    `json:"username,omitempty"`
 	Points      int64     `json:"points,omitempty"`
+	Balance     int64     `json:"balance"`
 	Referral_id int64     `json:"referral_id"`
 	Created_at  time.Time `json:"created_at"`
 }
 
 type Leaderboard interface {
-	GetLeaderboard() ([]models.User, error)
+	GetLeaderboard(currency string) ([]models.LeaderboardEntry, error)
 }
 
 func NewLeaderboard(log *slog.Logger, leaderboard Leaderboard) http.HandlerFunc {
@@ -35,7 +39,19 @@ func NewLeaderboard(log *slog.Logger, leaderboard Leaderboard) http.HandlerFunc
 			slog.String("op", op),
 		)
 
-		resLeaderboard, err := leaderboard.GetLeaderboard()
+		currency := r.URL.Query().Get("currency")
+		if currency == "" {
+			currency = models.CurrencyPoints
+		}
+
+		resLeaderboard, err := leaderboard.GetLeaderboard(currency)
+		if errors.Is(err, storage.ErrUnknownCurrency) {
+			log.Info("unknown currency", slog.String("currency", currency))
+
+			render.JSON(w, r, response.Error("unknown currency"))
+
+			return
+		}
 		if err != nil {
 			log.Error("failed to get Leaderboard", sl.Err(err))
 
@@ -54,6 +70,7 @@ func NewLeaderboard(log *slog.Logger, leaderboard Leaderboard) http.HandlerFunc
 			users = append(users, UserData{
 				Username:    user.Username,
 				Points:      user.Points,
+				Balance:     user.Balance,
 				Referral_id: user.Referral_id,
 				Created_at:  user.Created_at,
 			})
@@ -61,6 +78,7 @@ func NewLeaderboard(log *slog.Logger, leaderboard Leaderboard) http.HandlerFunc
 
 		render.JSON(w, r, Response{
 			Response: response.OK(),
+			Currency: currency,
 			Users:    users,
 		})
 	}
@@ -73,7 +73,7 @@ func NewLogin(log *slog.Logger, uSERLogin USERLogin) http.HandlerFunc {
 			return
 		}
 
-		token, err := middlewares.GenerateJWT(resUSER.Username)
+		token, err := middlewares.GenerateJWT(resUSER.Username, resUSER.IsAdmin)
 		if err != nil {
 			render.JSON(w, r, response.Error("Failed to generate token"))
 			return
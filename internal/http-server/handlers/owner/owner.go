@@ -0,0 +1,42 @@
+package owner
+
+import (
+	middlewares "denet/internal/http-server/middleware"
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+type UserGetter interface {
+	GetUSER(id int64) (*models.User, error)
+}
+
+// Check loads user id and makes sure the caller is logged in as that user.
+// Otherwise it writes the error response, using denied for the 403, and
+// reports false.
+func Check(w http.ResponseWriter, r *http.Request, log *slog.Logger, users UserGetter, id int64, denied string) (*models.User, bool) {
+	user, err := users.GetUSER(id)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		log.Info("user not found", slog.Int64("id", id))
+		render.JSON(w, r, response.Error("user not found"))
+		return nil, false
+	}
+	if err != nil {
+		log.Error("failed to get user", sl.Err(err))
+		render.JSON(w, r, response.Error("internal error"))
+		return nil, false
+	}
+	if user.Username != middlewares.Subject(r.Context()) {
+		log.Info("access to another user's account", slog.Int64("id", id))
+		render.Status(r, http.StatusForbidden)
+		render.JSON(w, r, response.Error(denied))
+		return nil, false
+	}
+	return user, true
+}
@@ -0,0 +1,175 @@
+package redemptions
+
+import (
+	"denet/internal/http-server/handlers/owner"
+	middlewares "denet/internal/http-server/middleware"
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+type ListResponse struct {
+	response.Response
+	Redemptions []models.Redemption `json:"redemptions"`
+}
+
+type StatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=fulfilled cancelled"`
+	Note   string `json:"note" validate:"max=1000"`
+}
+
+type RedemptionLister interface {
+	ListRedemptions(status string) ([]models.Redemption, error)
+}
+
+type UserRedemptions interface {
+	GetUSER(id int64) (*models.User, error)
+	GetUserRedemptions(userID int64) ([]models.Redemption, error)
+}
+
+type StatusUpdater interface {
+	UpdateRedemptionStatus(id int64, status string, admin string, note string) error
+}
+
+// NewList lists redemptions for admins, optionally filtered by status.
+func NewList(log *slog.Logger, lister RedemptionLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.redemptions.List"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		status := r.URL.Query().Get("status")
+		switch status {
+		case "", models.RedemptionPending, models.RedemptionFulfilled, models.RedemptionCancelled:
+		default:
+			log.Info("invalid status", slog.String("status", status))
+			render.JSON(w, r, response.Error("invalid status"))
+			return
+		}
+
+		redemptions, err := lister.ListRedemptions(status)
+		if err != nil {
+			log.Error("failed to list redemptions", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		render.JSON(w, r, ListResponse{
+			Response:    response.OK(),
+			Redemptions: redemptions,
+		})
+	}
+}
+
+// NewUserList lists the redemptions of one user. Only the user may see them,
+// since they include voucher codes.
+func NewUserList(log *slog.Logger, userRedemptions UserRedemptions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.redemptions.UserList"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		id, ok := pathID(w, r, log)
+		if !ok {
+			return
+		}
+		if _, ok := owner.Check(w, r, log, userRedemptions, id, "cannot list another user's redemptions"); !ok {
+			return
+		}
+
+		redemptions, err := userRedemptions.GetUserRedemptions(id)
+		if err != nil {
+			log.Error("failed to list user redemptions", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+		if redemptions == nil {
+			redemptions = []models.Redemption{}
+		}
+
+		render.JSON(w, r, ListResponse{
+			Response:    response.OK(),
+			Redemptions: redemptions,
+		})
+	}
+}
+
+// NewUpdateStatus lets an admin mark a pending redemption as fulfilled or
+// cancel it.
+func NewUpdateStatus(log *slog.Logger, updater StatusUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.redemptions.UpdateStatus"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		id, ok := pathID(w, r, log)
+		if !ok {
+			return
+		}
+
+		var req StatusRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.JSON(w, r, response.Error("failed to decode request: "+err.Error()))
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, response.ValidationError(validateErr))
+			return
+		}
+
+		admin := middlewares.Subject(r.Context())
+		err = updater.UpdateRedemptionStatus(id, req.Status, admin, req.Note)
+		if errors.Is(err, storage.ErrRedemptionNotFound) {
+			log.Info("redemption not found", slog.Int64("id", id))
+			render.JSON(w, r, response.Error("redemption not found"))
+			return
+		}
+		if errors.Is(err, storage.ErrRedemptionFinalized) {
+			log.Info("redemption already finalized", slog.Int64("id", id))
+			render.JSON(w, r, response.Error("redemption is already fulfilled or cancelled"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to update redemption", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		log.Info("redemption updated", slog.Int64("id", id), slog.String("status", req.Status), slog.String("admin", admin))
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+func pathID(w http.ResponseWriter, r *http.Request, log *slog.Logger) (int64, bool) {
+	ids := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(ids, 10, 64)
+	if err != nil {
+		log.Error("invalid id format", slog.String("id", ids))
+		render.JSON(w, r, response.Error("invalid id format"))
+		return 0, false
+	}
+	return id, true
+}
@@ -3,6 +3,8 @@ package referrer
 import (
 	"denet/internal/lib/api/response"
 	"denet/internal/lib/logger/sl"
+	"denet/internal/storage"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -70,6 +72,26 @@ func NewReferalTask(log *slog.Logger, referalTask ReferalTask) http.HandlerFunc
 		referalidInt64 := int64(referealid)
 
 		err = referalTask.SetReferral(id, referalidInt64)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.Int64("id", id))
+			render.JSON(w, r, response.Error("user not found"))
+			return
+		}
+		if errors.Is(err, storage.ErrReferrerNotFound) {
+			log.Info("referrer not found", slog.Int64("referalId", referalidInt64))
+			render.JSON(w, r, response.Error("referrer not found"))
+			return
+		}
+		if errors.Is(err, storage.ErrSelfReferral) {
+			log.Info("self referral", slog.Int64("id", id))
+			render.JSON(w, r, response.Error("user cannot refer themselves"))
+			return
+		}
+		if errors.Is(err, storage.ErrReferralSet) {
+			log.Info("referrer already set", slog.Int64("id", id))
+			render.JSON(w, r, response.Error("referrer is already set"))
+			return
+		}
 		if err != nil {
 			log.Error("failed to enter referalid: ", sl.Err(err))
 			render.JSON(w, r, response.Error("internal error: "+err.Error()))
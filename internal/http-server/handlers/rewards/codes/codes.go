@@ -0,0 +1,134 @@
+package codes
+
+import (
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/storage"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+const (
+	// maxUploadSize bounds the CSV accepted in one upload.
+	maxUploadSize = 10 << 20
+	maxCodeLength = 255
+)
+
+type Response struct {
+	response.Response
+	Received int64 `json:"received"`
+	Added    int64 `json:"added"`
+}
+
+type CodeUploader interface {
+	AddRewardCodes(rewardID int64, codes []string) (int64, error)
+}
+
+// NewUpload adds voucher codes to a reward from a CSV sent either as the
+// request body or as the "file" field of a multipart form. The first column
+// of each row is the code; a leading "code" header is skipped.
+func NewUpload(log *slog.Logger, codeUploader CodeUploader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.rewards.codes.Upload"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		ids := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(ids, 10, 64)
+		if err != nil {
+			log.Error("invalid id format", slog.String("id", ids))
+			render.JSON(w, r, response.Error("invalid id format"))
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+
+		var body io.Reader = r.Body
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				log.Error("failed to read uploaded file", sl.Err(err))
+				render.JSON(w, r, response.Error("failed to read uploaded file"))
+				return
+			}
+			defer file.Close()
+			body = file
+		}
+
+		codes, err := parseCodes(body)
+		if err != nil {
+			log.Error("failed to parse codes", sl.Err(err))
+			render.JSON(w, r, response.Error("failed to parse csv: "+err.Error()))
+			return
+		}
+		if len(codes) == 0 {
+			log.Info("no codes in upload", slog.Int64("reward", id))
+			render.JSON(w, r, response.Error("no codes found"))
+			return
+		}
+
+		added, err := codeUploader.AddRewardCodes(id, codes)
+		if errors.Is(err, storage.ErrRewardNotFound) {
+			log.Info("reward not found", slog.Int64("reward", id))
+			render.JSON(w, r, response.Error("reward not found"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to add codes", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		log.Info("codes added", slog.Int64("reward", id), slog.Int("received", len(codes)), slog.Int64("added", added))
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Received: int64(len(codes)),
+			Added:    added,
+		})
+	}
+}
+
+func parseCodes(body io.Reader) ([]string, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var codes []string
+	seen := make(map[string]bool)
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		code := strings.TrimSpace(record[0])
+		if first && strings.EqualFold(code, "code") {
+			continue
+		}
+		if code == "" || seen[code] {
+			continue
+		}
+		if len(code) > maxCodeLength {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("code on line %d is longer than %d characters", line, maxCodeLength)
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
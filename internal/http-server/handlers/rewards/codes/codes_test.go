@@ -0,0 +1,80 @@
+package codes
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseCodes(t *testing.T) {
+	long := strings.Repeat("x", maxCodeLength+1)
+
+	tests := []struct {
+		name    string
+		body    string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "one code per line",
+			body: "AAA\nBBB\nCCC\n",
+			want: []string{"AAA", "BBB", "CCC"},
+		},
+		{
+			name: "header skipped",
+			body: "code,note\nAAA,first\nBBB,second\n",
+			want: []string{"AAA", "BBB"},
+		},
+		{
+			name: "header in any case",
+			body: " Code \nAAA\n",
+			want: []string{"AAA"},
+		},
+		{
+			name: "code column only on the first line",
+			body: "AAA\ncode\n",
+			want: []string{"AAA", "code"},
+		},
+		{
+			name: "trimmed",
+			body: "  AAA  \n\tBBB\t,x\n",
+			want: []string{"AAA", "BBB"},
+		},
+		{
+			name: "duplicates and blanks dropped",
+			body: "AAA\n\nBBB\nAAA\n  AAA\n,\n",
+			want: []string{"AAA", "BBB"},
+		},
+		{
+			name: "longest code accepted",
+			body: strings.Repeat("x", maxCodeLength) + "\n",
+			want: []string{strings.Repeat("x", maxCodeLength)},
+		},
+		{
+			name:    "too long with line number",
+			body:    "code\nAAA\nBBB\n" + long + "\n",
+			wantErr: "code on line 4 is longer than 255 characters",
+		},
+		{
+			name: "empty",
+			body: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCodes(strings.NewReader(tt.body))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCodes: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("codes = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
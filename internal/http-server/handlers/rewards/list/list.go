@@ -0,0 +1,46 @@
+package list
+
+import (
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	response.Response
+	Rewards []models.Reward `json:"rewards"`
+}
+
+type RewardLister interface {
+	ListRewards(all bool) ([]models.Reward, error)
+}
+
+// NewList returns the active rewards of the shop.
+func NewList(log *slog.Logger, rewardLister RewardLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.rewards.list.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		rewards, err := rewardLister.ListRewards(false)
+		if err != nil {
+			log.Error("failed to list rewards", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+		if rewards == nil {
+			rewards = []models.Reward{}
+		}
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Rewards:  rewards,
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package redeem
+
+import (
+	"denet/internal/http-server/handlers/owner"
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	response.Response
+	Message    string             `json:"message,omitempty"`
+	Redemption *models.Redemption `json:"redemption,omitempty"`
+}
+
+type RewardRedeemer interface {
+	GetUSER(id int64) (*models.User, error)
+	RedeemReward(userID int64, rewardID int64) (*models.Redemption, error)
+}
+
+func NewRedeem(log *slog.Logger, rewardRedeemer RewardRedeemer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.rewards.redeem.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("Request received", slog.String("users", r.URL.String()))
+		ids := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(ids, 10, 64)
+		if err != nil {
+			log.Error("invalid id format", slog.String("id", ids))
+			render.JSON(w, r, response.Error("invalid id format"))
+			return
+		}
+
+		rewardIds := chi.URLParam(r, "rewardId")
+		rewardID, err := strconv.ParseInt(rewardIds, 10, 64)
+		if err != nil {
+			log.Error("invalid reward id format", slog.String("rewardId", rewardIds))
+			render.JSON(w, r, response.Error("invalid reward id format"))
+			return
+		}
+
+		// Only the owner of the account may spend its points.
+		if _, ok := owner.Check(w, r, log, rewardRedeemer, id, "cannot redeem rewards for another user's account"); !ok {
+			return
+		}
+
+		redemption, err := rewardRedeemer.RedeemReward(id, rewardID)
+		if err != nil {
+			if msg, ok := redeemMessage(err); ok {
+				log.Info("reward cannot be redeemed", slog.Int64("id", id), slog.Int64("reward", rewardID), sl.Err(err))
+				render.JSON(w, r, response.Error(msg))
+				return
+			}
+			log.Error("failed to redeem reward", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		log.Info("reward redeemed", slog.Int64("redemption", redemption.Id), slog.Int64("price", redemption.Price))
+
+		render.JSON(w, r, Response{
+			Response:   response.OK(),
+			Message:    "Successfully redeemed reward, spent point : " + strconv.FormatInt(redemption.Price, 10),
+			Redemption: redemption,
+		})
+	}
+}
+
+func redeemMessage(err error) (string, bool) {
+	switch {
+	case errors.Is(err, storage.ErrUserNotFound):
+		return "user not found", true
+	case errors.Is(err, storage.ErrRewardNotFound):
+		return "reward not found", true
+	case errors.Is(err, storage.ErrOutOfStock):
+		return "reward is out of stock", true
+	case errors.Is(err, storage.ErrInsufficientFunds):
+		return "insufficient funds", true
+	}
+	return "", false
+}
@@ -0,0 +1,85 @@
+package save
+
+import (
+	resp "denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+type Request struct {
+	Slug        string `json:"slug" validate:"required"`
+	Title       string `json:"title" validate:"required"`
+	Description string `json:"description"`
+	Price       int64  `json:"price" validate:"required,gt=0"`
+	Stock       int64  `json:"stock" validate:"min=0"`
+	Inactive    bool   `json:"inactive"`
+}
+
+type Response struct {
+	resp.Response
+	Id int64 `json:"id,omitempty"`
+}
+
+type RewardSaver interface {
+	CreateReward(reward models.Reward) (int64, error)
+}
+
+func New(log *slog.Logger, rewardSaver RewardSaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.rewards.save.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		var req Request
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.JSON(w, r, resp.Error("failed to decode request: "+err.Error()))
+			return
+		}
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+			return
+		}
+
+		id, err := rewardSaver.CreateReward(models.Reward{
+			Slug:        req.Slug,
+			Title:       req.Title,
+			Description: req.Description,
+			Price:       req.Price,
+			Stock:       req.Stock,
+			Active:      !req.Inactive,
+		})
+		if errors.Is(err, storage.ErrRewardExists) {
+			log.Info("reward already exists", slog.String("slug", req.Slug))
+			render.JSON(w, r, resp.Error("reward already exists"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to add reward", sl.Err(err))
+			render.JSON(w, r, resp.Error("failed to add reward"))
+			return
+		}
+
+		log.Info("reward added", slog.Int64("id", id))
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Id:       id,
+		})
+	}
+}
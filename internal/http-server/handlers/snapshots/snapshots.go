@@ -0,0 +1,132 @@
+package snapshots
+
+import (
+	middlewares "denet/internal/http-server/middleware"
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+type CreateRequest struct {
+	At       time.Time `json:"at" validate:"required"`
+	Currency string    `json:"currency"`
+}
+
+type Response struct {
+	response.Response
+	Snapshot *models.Snapshot `json:"snapshot,omitempty"`
+}
+
+type SnapshotCreator interface {
+	CreateSnapshot(currency string, at time.Time, admin string) (*models.Snapshot, error)
+}
+
+type SnapshotGetter interface {
+	GetSnapshot(id int64) (*models.Snapshot, error)
+}
+
+// NewCreate materialises the leaderboard of a currency as of a past moment.
+func NewCreate(log *slog.Logger, creator SnapshotCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.snapshots.Create"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		var req CreateRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.JSON(w, r, response.Error("failed to decode request: "+err.Error()))
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, response.ValidationError(validateErr))
+			return
+		}
+		if req.Currency == "" {
+			req.Currency = models.CurrencyPoints
+		}
+		// Balances of a moment that has not passed yet would still change.
+		if req.At.After(time.Now()) {
+			log.Info("snapshot in the future", slog.Time("at", req.At))
+			render.JSON(w, r, response.Error("at must not be in the future"))
+			return
+		}
+
+		admin := middlewares.Subject(r.Context())
+		snapshot, err := creator.CreateSnapshot(req.Currency, req.At, admin)
+		if errors.Is(err, storage.ErrUnknownCurrency) {
+			log.Info("unknown currency", slog.String("currency", req.Currency))
+			render.JSON(w, r, response.Error("unknown currency"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to create snapshot", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		log.Info("snapshot created", slog.Int64("id", snapshot.Id), slog.Int64("users", snapshot.Users), slog.String("admin", admin))
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Snapshot: snapshot,
+		})
+	}
+}
+
+// NewGet returns a snapshot with all of its entries.
+func NewGet(log *slog.Logger, getter SnapshotGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.snapshots.Get"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		ids := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(ids, 10, 64)
+		if err != nil {
+			log.Error("invalid id format", slog.String("id", ids))
+			render.JSON(w, r, response.Error("invalid id format"))
+			return
+		}
+
+		snapshot, err := getter.GetSnapshot(id)
+		if errors.Is(err, storage.ErrSnapshotNotFound) {
+			log.Info("snapshot not found", slog.Int64("id", id))
+			render.JSON(w, r, response.Error("snapshot not found"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to get snapshot", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+		if snapshot.Entries == nil {
+			snapshot.Entries = []models.SnapshotEntry{}
+		}
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Snapshot: snapshot,
+		})
+	}
+}
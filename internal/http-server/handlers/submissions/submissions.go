@@ -0,0 +1,179 @@
+package submissions
+
+import (
+	"denet/internal/http-server/handlers/task"
+	middlewares "denet/internal/http-server/middleware"
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+type ListResponse struct {
+	response.Response
+	Submissions []models.Submission `json:"submissions"`
+}
+
+type RejectRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+type SubmissionLister interface {
+	ListSubmissions(status string) ([]models.Submission, error)
+}
+
+type SubmissionReviewer interface {
+	ApproveSubmission(id int64, reviewer string) (*models.Completion, error)
+	RejectSubmission(id int64, reviewer string, reason string) error
+}
+
+func NewList(log *slog.Logger, lister SubmissionLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.submissions.List"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		status := r.URL.Query().Get("status")
+		switch status {
+		case "", models.SubmissionPending, models.SubmissionApproved, models.SubmissionRejected:
+		default:
+			log.Info("invalid status", slog.String("status", status))
+			render.JSON(w, r, response.Error("invalid status"))
+			return
+		}
+
+		submissions, err := lister.ListSubmissions(status)
+		if err != nil {
+			log.Error("failed to list submissions", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		render.JSON(w, r, ListResponse{
+			Response:    response.OK(),
+			Submissions: submissions,
+		})
+	}
+}
+
+func NewApprove(log *slog.Logger, reviewer SubmissionReviewer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.submissions.Approve"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		id, ok := submissionID(w, r, log)
+		if !ok {
+			return
+		}
+
+		admin := middlewares.Subject(r.Context())
+		completion, err := reviewer.ApproveSubmission(id, admin)
+		if err != nil {
+			if res, ok := reviewError(err); ok {
+				log.Info("submission cannot be approved", slog.Int64("id", id), sl.Err(err))
+				render.JSON(w, r, res)
+				return
+			}
+			log.Error("failed to approve submission", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		log.Info("submission approved", slog.Int64("id", id), slog.String("admin", admin), slog.Int64("points", completion.Points))
+
+		render.JSON(w, r, task.Response{
+			Response: response.OK(),
+			Message:  fmt.Sprintf("Submission approved, added point : %d", completion.Points),
+			Points:   completion.Points,
+			Base:     completion.Base,
+			Bonus:    completion.Bonus,
+			Streak:   completion.Streak,
+			Rewards:  completion.Rewards,
+			Boosts:   completion.Boosts,
+		})
+	}
+}
+
+func NewReject(log *slog.Logger, reviewer SubmissionReviewer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.submissions.Reject"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		id, ok := submissionID(w, r, log)
+		if !ok {
+			return
+		}
+
+		var req RejectRequest
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.JSON(w, r, response.Error("failed to decode request: "+err.Error()))
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, response.ValidationError(validateErr))
+			return
+		}
+
+		admin := middlewares.Subject(r.Context())
+		err = reviewer.RejectSubmission(id, admin, req.Reason)
+		if err != nil {
+			if res, ok := reviewError(err); ok {
+				log.Info("submission cannot be rejected", slog.Int64("id", id), sl.Err(err))
+				render.JSON(w, r, res)
+				return
+			}
+			log.Error("failed to reject submission", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		log.Info("submission rejected", slog.Int64("id", id), slog.String("admin", admin))
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+func submissionID(w http.ResponseWriter, r *http.Request, log *slog.Logger) (int64, bool) {
+	ids := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(ids, 10, 64)
+	if err != nil {
+		log.Error("invalid id format", slog.String("id", ids))
+		render.JSON(w, r, response.Error("invalid id format"))
+		return 0, false
+	}
+	return id, true
+}
+
+func reviewError(err error) (task.Response, bool) {
+	switch {
+	case errors.Is(err, storage.ErrSubmissionNotFound):
+		return task.Response{Response: response.Error("submission not found")}, true
+	case errors.Is(err, storage.ErrSubmissionReviewed):
+		return task.Response{Response: response.Error("submission is already reviewed")}, true
+	}
+	return task.CompletionError(err)
+}
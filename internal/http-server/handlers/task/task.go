@@ -1,12 +1,18 @@
 package task
 
 import (
+	"context"
 	"denet/internal/lib/api/response"
 	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"denet/internal/verifier"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -14,19 +20,43 @@ import (
 )
 
 type Request struct {
-	Points int64 `json:"points" validate:"required"`
+	TaskId int64  `json:"taskId" validate:"required"`
+	Proof  *Proof `json:"proof,omitempty"`
+}
+
+// Proof is required for tasks of type manual and is checked by a moderator.
+type Proof struct {
+	URL  string `json:"url" validate:"omitempty,url"`
+	Text string `json:"text" validate:"max=4000"`
 }
 
 type Response struct {
 	response.Response
-	Message string `json:"message,omitempty"`
+	Message         string     `json:"message,omitempty"`
+	Points          int64      `json:"points,omitempty"`
+	Base            int64      `json:"base,omitempty"`
+	Bonus           int64      `json:"bonus,omitempty"`
+	Streak          int64      `json:"streak,omitempty"`
+	NextAvailableAt *time.Time `json:"next_available_at,omitempty"`
+	Missing         []string   `json:"missing_prerequisites,omitempty"`
+	SubmissionId    int64      `json:"submission_id,omitempty"`
+	// Rewards lists the currencies other than points granted by the task.
+	Rewards map[string]int64 `json:"rewards,omitempty"`
+	// Boosts names the multipliers that raised Points above Base.
+	Boosts []string `json:"boosts,omitempty"`
 }
 
 type USERTask interface {
-	CompleteTask(userID int64, taskPoints int64) error
+	GetTask(id int64) (*models.Task, error)
+	CompleteTask(userID int64, taskID int64) (*models.Completion, error)
+	SubmitTask(userID int64, taskID int64, proofURL string, proofText string) (int64, error)
+}
+
+type TaskVerifier interface {
+	Verify(ctx context.Context, userID int64, task models.Task) error
 }
 
-func NewTask(log *slog.Logger, uSERTask USERTask) http.HandlerFunc {
+func NewTask(log *slog.Logger, uSERTask USERTask, taskVerifier TaskVerifier) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.user.task.New"
 
@@ -67,21 +97,147 @@ func NewTask(log *slog.Logger, uSERTask USERTask) http.HandlerFunc {
 			return
 		}
 
-		points := req.Points
-		pointsInt64 := int64(points)
+		task, err := uSERTask.GetTask(req.TaskId)
+		if errors.Is(err, storage.ErrTaskNotFound) {
+			log.Info("task not found", slog.Int64("task", req.TaskId))
+			render.JSON(w, r, response.Error("task not found"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to get task", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		if task.ProgressBased() {
+			log.Info("task completes automatically", slog.Int64("task", req.TaskId))
+			render.JSON(w, r, response.Error("task completes automatically"))
+			return
+		}
+
+		if task.Type == verifier.TypeManual {
+			if req.Proof == nil || (req.Proof.URL == "" && req.Proof.Text == "") {
+				log.Info("proof is missing", slog.Int64("id", id), slog.Int64("task", req.TaskId))
+				render.JSON(w, r, response.Error("proof is required for this task"))
+				return
+			}
+
+			submissionID, err := uSERTask.SubmitTask(id, req.TaskId, req.Proof.URL, req.Proof.Text)
+			if err != nil {
+				if res, ok := CompletionError(err); ok {
+					log.Info("task cannot be submitted", slog.Int64("id", id), slog.Int64("task", req.TaskId), sl.Err(err))
+					render.JSON(w, r, res)
+					return
+				}
+				log.Error("failed to submit task", sl.Err(err))
+				render.JSON(w, r, response.Error("internal error"))
+				return
+			}
+
+			log.Info("task submitted for review", slog.Int64("submission", submissionID))
+
+			render.JSON(w, r, Response{
+				Response:     response.OK(),
+				Message:      "Task submitted for review",
+				SubmissionId: submissionID,
+			})
+			return
+		}
+
+		err = taskVerifier.Verify(r.Context(), id, *task)
+		if err != nil {
+			log.Info("task verification failed", slog.Int64("id", id), slog.String("type", task.Type), sl.Err(err))
+			render.JSON(w, r, response.Error(verificationMessage(err)))
+			return
+		}
 
-		err = uSERTask.CompleteTask(id, pointsInt64)
+		completion, err := uSERTask.CompleteTask(id, req.TaskId)
 		if err != nil {
+			if res, ok := CompletionError(err); ok {
+				log.Info("task cannot be completed", slog.Int64("id", id), slog.Int64("task", req.TaskId), sl.Err(err))
+				render.JSON(w, r, res)
+				return
+			}
 			log.Error("failed to completeTask ", sl.Err(err))
 			render.JSON(w, r, response.Error("internal error: "+err.Error()))
 			return
 		}
 
-		log.Info("completed task, added point : ", slog.String("point:", fmt.Sprintf("%d", points)))
+		log.Info("completed task, added point : ", slog.String("point:", fmt.Sprintf("%d", completion.Points)), slog.Int64("streak", completion.Streak))
 
-		render.JSON(w, r, Response{
+		res := Response{
 			Response: response.OK(),
-			Message:  "Successfully completed task, added point : " + fmt.Sprintf("%d", points),
-		})
+			Message:  "Successfully completed task, added point : " + fmt.Sprintf("%d", completion.Points),
+			Points:   completion.Points,
+			Base:     completion.Base,
+			Bonus:    completion.Bonus,
+			Streak:   completion.Streak,
+			Rewards:  completion.Rewards,
+			Boosts:   completion.Boosts,
+		}
+		if !completion.NextAvailableAt.IsZero() {
+			res.NextAvailableAt = &completion.NextAvailableAt
+		}
+		render.JSON(w, r, res)
+	}
+}
+
+// CompletionError maps the storage errors of a refused completion to the
+// response sent to the client. It reports false for unexpected errors.
+func CompletionError(err error) (Response, bool) {
+	var cooldownErr *storage.CooldownError
+	var prerequisitesErr *storage.PrerequisitesError
+
+	switch {
+	case errors.As(err, &cooldownErr):
+		until := cooldownErr.Until
+		return Response{
+			Response:        response.Error("task is on cooldown until " + until.Format(time.RFC3339)),
+			NextAvailableAt: &until,
+		}, true
+	case errors.As(err, &prerequisitesErr):
+		missing := make([]string, 0, len(prerequisitesErr.Missing))
+		for _, task := range prerequisitesErr.Missing {
+			missing = append(missing, task.Slug)
+		}
+		return Response{
+			Response: response.Error("task prerequisites are not completed"),
+			Missing:  missing,
+		}, true
+	case errors.Is(err, storage.ErrTaskNotFound):
+		return Response{Response: response.Error("task not found")}, true
+	case errors.Is(err, storage.ErrTaskNotStarted):
+		return Response{Response: response.Error("task has not started yet")}, true
+	case errors.Is(err, storage.ErrTaskExpired):
+		return Response{Response: response.Error("task has expired")}, true
+	case errors.Is(err, storage.ErrTaskExhausted):
+		return Response{Response: response.Error("task completion limit reached")}, true
+	case errors.Is(err, storage.ErrTaskAutoCompleted):
+		return Response{Response: response.Error("task completes automatically")}, true
+	case errors.Is(err, storage.ErrTaskAlreadyCompleted):
+		return Response{Response: response.Error("task already completed")}, true
+	case errors.Is(err, storage.ErrSubmissionPending):
+		return Response{Response: response.Error("task is already waiting for review")}, true
+	case errors.Is(err, storage.ErrUserNotFound):
+		return Response{Response: response.Error("user not found")}, true
+	}
+	return Response{}, false
+}
+
+func verificationMessage(err error) string {
+	switch {
+	case errors.Is(err, verifier.ErrManualReview):
+		return "task requires manual review"
+	case errors.Is(err, verifier.ErrUnknownType):
+		return "task cannot be verified"
+	case errors.Is(err, verifier.ErrAccountNotLinked):
+		return "external account is not linked"
+	case errors.Is(err, verifier.ErrNotMember):
+		return "user is not a member of the channel"
+	case errors.Is(err, verifier.ErrUpstreamUnavailable):
+		return "verification service is unavailable, try again later"
+	case errors.Is(err, verifier.ErrNotVerified):
+		return "task is not verified"
 	}
+	return "failed to verify task"
 }
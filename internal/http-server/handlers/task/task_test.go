@@ -0,0 +1,97 @@
+package task
+
+import (
+	"bytes"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"denet/internal/verifier"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type stubTasks struct {
+	task      models.Task
+	completed []int64
+}
+
+func (s *stubTasks) GetTask(id int64) (*models.Task, error) {
+	if id != s.task.Id {
+		return nil, storage.ErrTaskNotFound
+	}
+	task := s.task
+	return &task, nil
+}
+
+func (s *stubTasks) CompleteTask(userID int64, taskID int64) (*models.Completion, error) {
+	s.completed = append(s.completed, userID)
+	return &models.Completion{Points: s.task.Reward, Base: s.task.Reward, Streak: 1}, nil
+}
+
+func (s *stubTasks) SubmitTask(userID int64, taskID int64, proofURL string, proofText string) (int64, error) {
+	return 0, fmt.Errorf("unexpected submission")
+}
+
+func completeTask(t *testing.T, tasks USERTask, v TaskVerifier) Response {
+	t.Helper()
+
+	router := chi.NewRouter()
+	router.Post("/users/{id}/task/complete", NewTask(slog.New(slog.NewTextHandler(io.Discard, nil)), tasks, v))
+
+	req := httptest.NewRequest(http.MethodPost, "/users/7/task/complete", bytes.NewBufferString(`{"taskId": 1}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var res Response
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return res
+}
+
+func TestNewTaskVerification(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantError string
+		completed bool
+	}{
+		{name: "accepted", completed: true},
+		{name: "rejected", err: verifier.ErrNotMember, wantError: "user is not a member of the channel"},
+		{name: "upstream error", err: verifier.ErrUpstreamUnavailable, wantError: "verification service is unavailable, try again later"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := &stubTasks{task: models.Task{Id: 1, Slug: "subscribe_telegram", Type: verifier.TypeTelegramSubscription, Reward: 10}}
+			fake := &verifier.Fake{Err: tt.err}
+
+			res := completeTask(t, tasks, fake)
+
+			if res.Error != tt.wantError {
+				t.Errorf("error = %q, want %q", res.Error, tt.wantError)
+			}
+			if got := len(tasks.completed) == 1; got != tt.completed {
+				t.Errorf("completed = %v, want %v", got, tt.completed)
+			}
+			if tt.completed && res.Points != 10 {
+				t.Errorf("points = %d, want 10", res.Points)
+			}
+
+			calls := fake.Calls()
+			if len(calls) != 1 {
+				t.Fatalf("verifier called %d times, want 1", len(calls))
+			}
+			if calls[0].UserID != 7 || calls[0].Task.Slug != "subscribe_telegram" {
+				t.Errorf("verifier called with %+v", calls[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package list
+
+import (
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	response.Response
+	Tasks []models.UserTask `json:"tasks"`
+}
+
+type UserTasks interface {
+	ListUserTasks(userID int64) ([]models.UserTask, error)
+}
+
+func NewList(log *slog.Logger, userTasks UserTasks) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.tasks.list.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("Request received", slog.String("users", r.URL.String()))
+		ids := chi.URLParam(r, "id")
+		if ids == "" {
+			log.Info("id is empty")
+			render.JSON(w, r, response.Error("invalid request"))
+			return
+		}
+
+		id, err := strconv.ParseInt(ids, 10, 64)
+		if err != nil {
+			log.Error("invalid id format", slog.String("id", ids))
+			render.JSON(w, r, response.Error("invalid id format"))
+			return
+		}
+
+		tasks, err := userTasks.ListUserTasks(id)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", "id", ids)
+			render.JSON(w, r, response.Error("user not found"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to list tasks", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Tasks:    tasks,
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package prerequisites
+
+import (
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/storage"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	Prerequisites []int64 `json:"prerequisites"`
+}
+
+type TaskPrerequisites interface {
+	SetTaskPrerequisites(taskID int64, prerequisites []int64) error
+}
+
+func NewSetPrerequisites(log *slog.Logger, taskPrerequisites TaskPrerequisites) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.tasks.prerequisites.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		ids := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(ids, 10, 64)
+		if err != nil {
+			log.Error("invalid id format", slog.String("id", ids))
+			render.JSON(w, r, response.Error("invalid id format"))
+			return
+		}
+
+		var req Request
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.JSON(w, r, response.Error("failed to decode request: "+err.Error()))
+			return
+		}
+		log.Info("request body decoded", slog.Any("request", req))
+
+		err = taskPrerequisites.SetTaskPrerequisites(id, req.Prerequisites)
+		if errors.Is(err, storage.ErrTaskNotFound) {
+			log.Info("task not found", sl.Err(err))
+			render.JSON(w, r, response.Error("task not found"))
+			return
+		}
+		if errors.Is(err, storage.ErrPrerequisiteCycle) {
+			log.Info("prerequisites form a cycle", sl.Err(err))
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+		if err != nil {
+			log.Error("failed to set prerequisites", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		log.Info("prerequisites updated", slog.Int64("id", id))
+		render.JSON(w, r, response.OK())
+	}
+}
@@ -0,0 +1,175 @@
+package save
+
+import (
+	resp "denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/lib/recurrence"
+	"denet/internal/storage"
+	"denet/internal/verifier"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+type Request struct {
+	Slug            string     `json:"slug" validate:"required"`
+	Title           string     `json:"title" validate:"required"`
+	Type            string     `json:"type"`
+	Target          string     `json:"target"`
+	Reward          int64      `json:"reward" validate:"min=0"`
+	Inactive        bool       `json:"inactive"`
+	Recurrence      string     `json:"recurrence"`
+	CooldownSeconds int64      `json:"cooldownSeconds" validate:"min=0"`
+	StreakBonus     int64      `json:"streakBonus" validate:"min=0"`
+	StartsAt        *time.Time `json:"startsAt"`
+	EndsAt          *time.Time `json:"endsAt"`
+	MaxCompletions  int64      `json:"maxCompletions" validate:"min=0"`
+	Counter         string     `json:"counter"`
+	TargetValue     int64      `json:"targetValue" validate:"min=0"`
+	ExpiryDays      int64      `json:"expiryDays" validate:"min=0"`
+	Prerequisites   []int64    `json:"prerequisites"`
+	// Rewards grants currencies other than points, keyed by currency code.
+	Rewards map[string]int64 `json:"rewards" validate:"dive,gt=0"`
+}
+
+type Response struct {
+	resp.Response
+	Id int64 `json:"id,omitempty"`
+}
+
+type TaskSaver interface {
+	CreateTask(task models.Task, prerequisites []int64) (int64, error)
+}
+
+func New(log *slog.Logger, taskSaver TaskSaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.tasks.save.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		var req Request
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.JSON(w, r, resp.Error("failed to decode request: "+err.Error()))
+			return
+		}
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, resp.ValidationError(validateErr))
+			return
+		}
+
+		task := models.Task{
+			Slug:            req.Slug,
+			Title:           req.Title,
+			Type:            req.Type,
+			Target:          req.Target,
+			Reward:          req.Reward,
+			Active:          !req.Inactive,
+			Recurrence:      req.Recurrence,
+			CooldownSeconds: req.CooldownSeconds,
+			StreakBonus:     req.StreakBonus,
+			StartsAt:        req.StartsAt,
+			EndsAt:          req.EndsAt,
+			MaxCompletions:  req.MaxCompletions,
+			Counter:         req.Counter,
+			TargetValue:     req.TargetValue,
+			ExpiryDays:      req.ExpiryDays,
+			Rewards:         req.Rewards,
+		}
+		if task.Type == "" {
+			task.Type = verifier.TypeNone
+		}
+		if task.Recurrence == "" {
+			task.Recurrence = recurrence.Once
+		}
+		if verifier.NeedsTarget(task.Type) && task.Target == "" {
+			log.Info("task target is missing", slog.String("type", task.Type))
+			render.JSON(w, r, resp.Error(task.Type+" tasks need a target"))
+			return
+		}
+		if !task.Policy().Valid() {
+			log.Info("invalid recurrence", slog.String("recurrence", task.Recurrence))
+			render.JSON(w, r, resp.Error("invalid recurrence"))
+			return
+		}
+
+		if (task.Counter == "") != (task.TargetValue == 0) {
+			log.Info("invalid progress settings", slog.String("counter", task.Counter), slog.Int64("targetValue", task.TargetValue))
+			render.JSON(w, r, resp.Error("counter and targetValue must be set together"))
+			return
+		}
+		if task.ProgressBased() && task.Recurrence != recurrence.Once {
+			log.Info("progress-based task must not recur", slog.String("recurrence", task.Recurrence))
+			render.JSON(w, r, resp.Error("progress-based tasks cannot recur"))
+			return
+		}
+
+		// Timestamps are stored without a time zone, always in UTC.
+		if task.StartsAt != nil {
+			startsAt := task.StartsAt.UTC()
+			task.StartsAt = &startsAt
+		}
+		if task.EndsAt != nil {
+			endsAt := task.EndsAt.UTC()
+			task.EndsAt = &endsAt
+		}
+		if task.StartsAt != nil && task.EndsAt != nil && !task.StartsAt.Before(*task.EndsAt) {
+			log.Info("invalid availability window")
+			render.JSON(w, r, resp.Error("startsAt must be before endsAt"))
+			return
+		}
+
+		if _, ok := task.Rewards[models.CurrencyPoints]; ok {
+			log.Info("points in rewards")
+			render.JSON(w, r, resp.Error("points are granted with reward, not rewards"))
+			return
+		}
+
+		id, err := taskSaver.CreateTask(task, req.Prerequisites)
+		if errors.Is(err, storage.ErrTaskExists) {
+			log.Info("task already exists", slog.String("slug", req.Slug))
+			render.JSON(w, r, resp.Error("task already exists"))
+			return
+		}
+		if errors.Is(err, storage.ErrTaskNotFound) {
+			log.Info("prerequisite not found", sl.Err(err))
+			render.JSON(w, r, resp.Error("prerequisite task not found"))
+			return
+		}
+		if errors.Is(err, storage.ErrUnknownCurrency) {
+			log.Info("unknown currency", sl.Err(err))
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+		if errors.Is(err, storage.ErrPrerequisiteCycle) {
+			log.Info("prerequisites form a cycle", sl.Err(err))
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+		if err != nil {
+			log.Error("failed to add task", sl.Err(err))
+			render.JSON(w, r, resp.Error("failed to add task"))
+			return
+		}
+
+		log.Info("task added", slog.Int64("id", id))
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Id:       id,
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package transfer
+
+import (
+	"denet/internal/config"
+	"denet/internal/http-server/handlers/owner"
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+type Request struct {
+	RecipientId int64  `json:"recipientId" validate:"required"`
+	Amount      int64  `json:"amount" validate:"required,gt=0"`
+	Memo        string `json:"memo" validate:"max=140"`
+}
+
+type Response struct {
+	response.Response
+	Message  string           `json:"message,omitempty"`
+	Transfer *models.Transfer `json:"transfer,omitempty"`
+}
+
+type USERTransfer interface {
+	GetUSER(id int64) (*models.User, error)
+	Transfer(fromID, toID, amount int64, memo string, limits models.TransferLimits) (*models.Transfer, error)
+}
+
+func New(log *slog.Logger, uSERTransfer USERTransfer, cfg config.Transfers) http.HandlerFunc {
+	limits := models.TransferLimits{
+		DailyAmount: cfg.DailyAmount,
+		DailyCount:  cfg.DailyCount,
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.transfer.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		log.Info("Request received", slog.String("users", r.URL.String()))
+		ids := chi.URLParam(r, "id")
+		if ids == "" {
+			log.Info("id is empty")
+			render.JSON(w, r, response.Error("invalid request"))
+			return
+		}
+
+		id, err := strconv.ParseInt(ids, 10, 64)
+		if err != nil {
+			log.Error("invalid id format", slog.String("id", ids))
+			render.JSON(w, r, response.Error("invalid id format"))
+			return
+		}
+
+		var req Request
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.JSON(w, r, response.Error("failed to decode request: "+err.Error()))
+			return
+		}
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, response.ValidationError(validateErr))
+			return
+		}
+
+		// Only the owner of the account may send its points.
+		if _, ok := owner.Check(w, r, log, uSERTransfer, id, "cannot transfer points from another user's account"); !ok {
+			return
+		}
+
+		transfer, err := uSERTransfer.Transfer(id, req.RecipientId, req.Amount, req.Memo, limits)
+		if err != nil {
+			if msg, ok := transferMessage(err); ok {
+				log.Info("transfer refused", slog.Int64("id", id), slog.Int64("recipient", req.RecipientId), sl.Err(err))
+				render.JSON(w, r, response.Error(msg))
+				return
+			}
+			log.Error("failed to transfer points", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		log.Info("points transferred", slog.Int64("transfer", transfer.Id), slog.Int64("amount", transfer.Amount))
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Message:  "Successfully transferred points : " + strconv.FormatInt(transfer.Amount, 10),
+			Transfer: transfer,
+		})
+	}
+}
+
+func transferMessage(err error) (string, bool) {
+	switch {
+	case errors.Is(err, storage.ErrSelfTransfer):
+		return "user cannot transfer points to themselves", true
+	case errors.Is(err, storage.ErrUserNotFound):
+		return "user not found", true
+	case errors.Is(err, storage.ErrRecipientNotFound):
+		return "recipient not found", true
+	case errors.Is(err, storage.ErrInsufficientFunds):
+		return "insufficient funds", true
+	case errors.Is(err, storage.ErrTransferLimitExceeded):
+		return "daily transfer limit exceeded", true
+	case errors.Is(err, storage.ErrTransferConflict):
+		return "transfer conflicted with another operation, try again", true
+	}
+	return "", false
+}
@@ -0,0 +1,111 @@
+package adjust
+
+import (
+	middlewares "denet/internal/http-server/middleware"
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+type Request struct {
+	Delta    int64  `json:"delta" validate:"required"`
+	Reason   string `json:"reason" validate:"required,max=140"`
+	Currency string `json:"currency"`
+	// Override allows the adjustment to leave the balance negative.
+	Override bool `json:"override"`
+}
+
+type Response struct {
+	response.Response
+	Entry   *models.LedgerEntry `json:"entry,omitempty"`
+	Balance int64               `json:"balance"`
+}
+
+type BalanceAdjuster interface {
+	AdjustBalance(userID int64, currency string, delta int64, note string, actor string, override bool) (*models.LedgerEntry, int64, error)
+}
+
+func New(log *slog.Logger, adjuster BalanceAdjuster) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.users.adjust.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		ids := chi.URLParam(r, "id")
+		id, err := strconv.ParseInt(ids, 10, 64)
+		if err != nil {
+			log.Error("invalid id format", slog.String("id", ids))
+			render.JSON(w, r, response.Error("invalid id format"))
+			return
+		}
+
+		var req Request
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.JSON(w, r, response.Error("failed to decode request: "+err.Error()))
+			return
+		}
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.Error("invalid request", sl.Err(err))
+
+			render.JSON(w, r, response.ValidationError(validateErr))
+			return
+		}
+		if req.Currency == "" {
+			req.Currency = models.CurrencyPoints
+		}
+
+		admin := middlewares.Subject(r.Context())
+		entry, balance, err := adjuster.AdjustBalance(id, req.Currency, req.Delta, req.Reason, admin, req.Override)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.Int64("id", id))
+			render.JSON(w, r, response.Error("user not found"))
+			return
+		}
+		if errors.Is(err, storage.ErrUnknownCurrency) {
+			log.Info("unknown currency", slog.String("currency", req.Currency))
+			render.JSON(w, r, response.Error("unknown currency"))
+			return
+		}
+		if errors.Is(err, storage.ErrInsufficientFunds) {
+			log.Info("adjustment would make balance negative", slog.Int64("id", id), slog.Int64("delta", req.Delta))
+			render.JSON(w, r, response.Error("adjustment would make the balance negative, set override to apply it anyway"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to adjust balance", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		log.Info("balance adjusted",
+			slog.Int64("id", id),
+			slog.String("currency", req.Currency),
+			slog.Int64("delta", req.Delta),
+			slog.String("admin", admin),
+			slog.Bool("override", req.Override),
+		)
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Entry:    entry,
+			Balance:  balance,
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"denet/internal/http-server/handlers/owner"
+	"denet/internal/lib/api/response"
+	"denet/internal/lib/eth"
+	"denet/internal/lib/logger/sl"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator"
+)
+
+// challengeTTL is how long a user has to sign a challenge.
+const challengeTTL = 10 * time.Minute
+
+type ChallengeRequest struct {
+	Address string `json:"address" validate:"required"`
+}
+
+type ChallengeResponse struct {
+	response.Response
+	Message   string    `json:"message,omitempty"`
+	Nonce     string    `json:"nonce,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+type VerifyRequest struct {
+	Address   string `json:"address" validate:"required"`
+	Signature string `json:"signature" validate:"required"`
+}
+
+type VerifyResponse struct {
+	response.Response
+	Address string `json:"address,omitempty"`
+}
+
+type ChallengeSaver interface {
+	GetUSER(id int64) (*models.User, error)
+	SaveWalletChallenge(challenge models.WalletChallenge) error
+}
+
+type WalletLinker interface {
+	GetUSER(id int64) (*models.User, error)
+	GetWalletChallenge(userID int64, now time.Time) (*models.WalletChallenge, error)
+	LinkWallet(userID int64, nonce string, now time.Time) (string, error)
+}
+
+// NewChallenge issues a message that the user signs with personal_sign to
+// prove they own the address.
+func NewChallenge(log *slog.Logger, saver ChallengeSaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.wallet.Challenge"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		user, ok := pathOwner(w, r, log, saver)
+		if !ok {
+			return
+		}
+
+		var req ChallengeRequest
+		if !decode(w, r, log, &req) {
+			return
+		}
+		address, err := eth.ParseAddress(req.Address)
+		if err != nil {
+			log.Info("invalid address", slog.String("address", req.Address))
+			render.JSON(w, r, response.Error("invalid address"))
+			return
+		}
+
+		nonce := make([]byte, 16)
+		if _, err := rand.Read(nonce); err != nil {
+			log.Error("failed to generate nonce", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		now := time.Now().UTC()
+		challenge := models.WalletChallenge{
+			UserId:    user.Id,
+			Address:   address.String(),
+			Nonce:     hex.EncodeToString(nonce),
+			ExpiresAt: now.Add(challengeTTL),
+			CreatedAt: now,
+		}
+		challenge.Message = fmt.Sprintf(
+			"Link this wallet to your DeNet account.\n\nUser: %s\nAddress: %s\nNonce: %s\nIssued at: %s",
+			user.Username, address.Hex(), challenge.Nonce, now.Format(time.RFC3339),
+		)
+
+		if err := saver.SaveWalletChallenge(challenge); err != nil {
+			log.Error("failed to save wallet challenge", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		render.JSON(w, r, ChallengeResponse{
+			Response:  response.OK(),
+			Message:   challenge.Message,
+			Nonce:     challenge.Nonce,
+			ExpiresAt: challenge.ExpiresAt,
+		})
+	}
+}
+
+// NewVerify links the wallet once the signature of the outstanding challenge
+// recovers to its address.
+func NewVerify(log *slog.Logger, linker WalletLinker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.wallet.Verify"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		user, ok := pathOwner(w, r, log, linker)
+		if !ok {
+			return
+		}
+
+		var req VerifyRequest
+		if !decode(w, r, log, &req) {
+			return
+		}
+		address, err := eth.ParseAddress(req.Address)
+		if err != nil {
+			log.Info("invalid address", slog.String("address", req.Address))
+			render.JSON(w, r, response.Error("invalid address"))
+			return
+		}
+		signature, err := hex.DecodeString(strings.TrimPrefix(req.Signature, "0x"))
+		if err != nil {
+			log.Info("invalid signature encoding")
+			render.JSON(w, r, response.Error("invalid signature"))
+			return
+		}
+
+		now := time.Now().UTC()
+		challenge, err := linker.GetWalletChallenge(user.Id, now)
+		if errors.Is(err, storage.ErrChallengeNotFound) {
+			log.Info("no wallet challenge", slog.Int64("id", user.Id))
+			render.JSON(w, r, response.Error("no active challenge, request a new one"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to get wallet challenge", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+		if challenge.Address != address.String() {
+			log.Info("address does not match challenge", slog.String("address", address.String()))
+			render.JSON(w, r, response.Error("address does not match the challenge"))
+			return
+		}
+
+		signer, err := eth.RecoverPersonal([]byte(challenge.Message), signature)
+		if err != nil || signer != address {
+			log.Info("signature does not match address", slog.String("address", address.String()))
+			render.JSON(w, r, response.Error("signature does not match the address"))
+			return
+		}
+
+		linked, err := linker.LinkWallet(user.Id, challenge.Nonce, now)
+		if errors.Is(err, storage.ErrChallengeNotFound) {
+			log.Info("wallet challenge already used", slog.Int64("id", user.Id))
+			render.JSON(w, r, response.Error("no active challenge, request a new one"))
+			return
+		}
+		if errors.Is(err, storage.ErrWalletTaken) {
+			log.Info("wallet linked to another user", slog.String("address", address.String()))
+			render.JSON(w, r, response.Error("wallet is linked to another user"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to link wallet", sl.Err(err))
+			render.JSON(w, r, response.Error("internal error"))
+			return
+		}
+
+		log.Info("wallet linked", slog.Int64("id", user.Id), slog.String("address", linked))
+
+		render.JSON(w, r, VerifyResponse{
+			Response: response.OK(),
+			Address:  linked,
+		})
+	}
+}
+
+// pathOwner parses the user ID of the path and checks that the caller owns
+// the account.
+func pathOwner(w http.ResponseWriter, r *http.Request, log *slog.Logger, users owner.UserGetter) (*models.User, bool) {
+	ids := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(ids, 10, 64)
+	if err != nil {
+		log.Error("invalid id format", slog.String("id", ids))
+		render.JSON(w, r, response.Error("invalid id format"))
+		return nil, false
+	}
+	return owner.Check(w, r, log, users, id, "cannot link a wallet to another user's account")
+}
+
+func decode(w http.ResponseWriter, r *http.Request, log *slog.Logger, req any) bool {
+	if err := render.DecodeJSON(r.Body, req); err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+		render.JSON(w, r, response.Error("failed to decode request: "+err.Error()))
+		return false
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		render.JSON(w, r, response.ValidationError(validateErr))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,200 @@
+package wallet
+
+import (
+	"bytes"
+	middlewares "denet/internal/http-server/middleware"
+	"denet/internal/lib/eth"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/go-chi/chi/v5"
+)
+
+// stubWallets keeps one challenge and consumes it on link, like the
+// wallet_challenges table.
+type stubWallets struct {
+	challenge *models.WalletChallenge
+	// consumed makes LinkWallet report the challenge as already used, as
+	// when a concurrent request linked it first.
+	consumed bool
+	linked   []string
+}
+
+func (s *stubWallets) GetUSER(id int64) (*models.User, error) {
+	if id != 7 {
+		return nil, storage.ErrUserNotFound
+	}
+	return &models.User{Id: 7, Username: "alice"}, nil
+}
+
+func (s *stubWallets) GetWalletChallenge(userID int64, now time.Time) (*models.WalletChallenge, error) {
+	if s.challenge == nil || s.challenge.UserId != userID || !s.challenge.ExpiresAt.After(now) {
+		return nil, storage.ErrChallengeNotFound
+	}
+	challenge := *s.challenge
+	return &challenge, nil
+}
+
+func (s *stubWallets) LinkWallet(userID int64, nonce string, now time.Time) (string, error) {
+	if s.consumed || s.challenge == nil || s.challenge.Nonce != nonce {
+		return "", storage.ErrChallengeNotFound
+	}
+	address := s.challenge.Address
+	s.challenge = nil
+	s.linked = append(s.linked, address)
+	return address, nil
+}
+
+type signer struct {
+	key     *secp256k1.PrivateKey
+	address eth.Address
+}
+
+func newSigner(t *testing.T, seed byte) signer {
+	t.Helper()
+
+	priv := secp256k1.PrivKeyFromBytes(bytes.Repeat([]byte{seed}, 32))
+	hash := eth.Keccak256(priv.PubKey().SerializeUncompressed()[1:])
+	var address eth.Address
+	copy(address[:], hash[12:])
+	return signer{key: priv, address: address}
+}
+
+// sign returns the personal_sign signature of msg as r || s || v hex.
+func (s signer) sign(msg string) string {
+	hash := eth.PersonalMessageHash([]byte(msg))
+	compact := ecdsa.SignCompact(s.key, hash[:], false)
+	sig := append(compact[1:], compact[0])
+	return "0x" + hex.EncodeToString(sig)
+}
+
+func challengeFor(s signer, expiresAt time.Time) *models.WalletChallenge {
+	return &models.WalletChallenge{
+		UserId:    7,
+		Address:   s.address.String(),
+		Nonce:     "00112233445566778899aabbccddeeff",
+		Message:   "Link this wallet to your DeNet account.\n\nNonce: 00112233445566778899aabbccddeeff",
+		ExpiresAt: expiresAt,
+	}
+}
+
+func verify(t *testing.T, wallets WalletLinker, address string, signature string) VerifyResponse {
+	t.Helper()
+
+	router := chi.NewRouter()
+	router.Use(middlewares.ValidateJWT)
+	router.Post("/users/{id}/wallet/verify", NewVerify(slog.New(slog.NewTextHandler(io.Discard, nil)), wallets))
+
+	token, err := middlewares.GenerateJWT("alice", false)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	body := fmt.Sprintf(`{"address": %q, "signature": %q}`, address, signature)
+	req := httptest.NewRequest(http.MethodPost, "/users/7/wallet/verify", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var res VerifyResponse
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return res
+}
+
+func TestNewVerify(t *testing.T) {
+	alice := newSigner(t, 1)
+	mallory := newSigner(t, 2)
+	future := time.Now().UTC().Add(challengeTTL)
+	msg := challengeFor(alice, future).Message
+
+	tests := []struct {
+		name      string
+		wallets   *stubWallets
+		address   string
+		signature string
+		wantErr   string
+	}{
+		{
+			name:      "linked",
+			wallets:   &stubWallets{challenge: challengeFor(alice, future)},
+			address:   alice.address.Hex(),
+			signature: alice.sign(msg),
+		},
+		{
+			name:      "address mismatch",
+			wallets:   &stubWallets{challenge: challengeFor(alice, future)},
+			address:   mallory.address.Hex(),
+			signature: mallory.sign(msg),
+			wantErr:   "address does not match the challenge",
+		},
+		{
+			name:      "signed by another key",
+			wallets:   &stubWallets{challenge: challengeFor(alice, future)},
+			address:   alice.address.Hex(),
+			signature: mallory.sign(msg),
+			wantErr:   "signature does not match the address",
+		},
+		{
+			name:      "expired challenge",
+			wallets:   &stubWallets{challenge: challengeFor(alice, time.Now().UTC().Add(-time.Second))},
+			address:   alice.address.Hex(),
+			signature: alice.sign(msg),
+			wantErr:   "no active challenge, request a new one",
+		},
+		{
+			name:      "challenge used concurrently",
+			wallets:   &stubWallets{challenge: challengeFor(alice, future), consumed: true},
+			address:   alice.address.Hex(),
+			signature: alice.sign(msg),
+			wantErr:   "no active challenge, request a new one",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := verify(t, tt.wallets, tt.address, tt.signature)
+			if res.Error != tt.wantErr {
+				t.Fatalf("error = %q, want %q", res.Error, tt.wantErr)
+			}
+			if tt.wantErr != "" {
+				if len(tt.wallets.linked) != 0 {
+					t.Errorf("wallet linked despite %q", tt.wantErr)
+				}
+				return
+			}
+			if res.Address != alice.address.String() {
+				t.Errorf("address = %q, want %q", res.Address, alice.address.String())
+			}
+		})
+	}
+}
+
+func TestNewVerifyReusedChallenge(t *testing.T) {
+	alice := newSigner(t, 1)
+	challenge := challengeFor(alice, time.Now().UTC().Add(challengeTTL))
+	wallets := &stubWallets{challenge: challenge}
+	signature := alice.sign(challenge.Message)
+
+	if res := verify(t, wallets, alice.address.Hex(), signature); res.Error != "" {
+		t.Fatalf("first verify: %q", res.Error)
+	}
+	res := verify(t, wallets, alice.address.Hex(), signature)
+	if want := "no active challenge, request a new one"; res.Error != want {
+		t.Errorf("replayed verify: error = %q, want %q", res.Error, want)
+	}
+	if len(wallets.linked) != 1 {
+		t.Errorf("wallet linked %d times, want 1", len(wallets.linked))
+	}
+}
@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"context"
 	"denet/internal/lib/api/response"
 	"fmt"
 	"net/http"
@@ -13,12 +14,17 @@ import (
 
 var jwtSecret = []byte("secret_1234")
 
-func GenerateJWT(userID string) (string, error) {
+type ctxKey int
+
+const claimsKey ctxKey = iota
+
+func GenerateJWT(userID string, isAdmin bool) (string, error) {
 	claims := jwt.MapClaims{
-		"sub": userID,                                 // Идентификатор пользователя
-		"exp": time.Now().Add(time.Minute * 4).Unix(), // Время жизни токена 1 минута
-		"nbf": time.Now().Unix(),
-		"iat": time.Now().Unix(),
+		"sub":   userID,                                 // Идентификатор пользователя
+		"exp":   time.Now().Add(time.Minute * 4).Unix(), // Время жизни токена 1 минута
+		"nbf":   time.Now().Unix(),
+		"iat":   time.Now().Unix(),
+		"admin": isAdmin,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -53,6 +59,35 @@ func ValidateJWT(next http.Handler) http.Handler {
 			return
 		}
 
+		claims, _ := token.Claims.(jwt.MapClaims)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsKey, claims)))
+	})
+}
+
+// RequireAdmin rejects requests whose token was not issued to an admin.
+// It must be mounted after ValidateJWT.
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !IsAdmin(r.Context()) {
+			render.Status(r, http.StatusForbidden)
+			render.JSON(w, r, response.Error("Admin access required"))
+			return
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
+
+// Subject returns the username the request token was issued to.
+func Subject(ctx context.Context) string {
+	claims, _ := ctx.Value(claimsKey).(jwt.MapClaims)
+	sub, _ := claims["sub"].(string)
+	return sub
+}
+
+// IsAdmin reports whether the request token carries the admin claim.
+func IsAdmin(ctx context.Context) bool {
+	claims, _ := ctx.Value(claimsKey).(jwt.MapClaims)
+	admin, _ := claims["admin"].(bool)
+	return admin
+}
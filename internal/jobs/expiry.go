@@ -0,0 +1,22 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type PointsExpirer interface {
+	ExpirePoints(now time.Time) (int64, error)
+}
+
+// ExpirePoints debits the unspent part of lapsed credits.
+func ExpirePoints(log *slog.Logger, expirer PointsExpirer) Job {
+	return func(ctx context.Context) error {
+		users, err := expirer.ExpirePoints(time.Now().UTC())
+		if users > 0 {
+			log.Info("points expired", slog.Int64("users", users))
+		}
+		return err
+	}
+}
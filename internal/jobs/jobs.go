@@ -0,0 +1,38 @@
+// Package jobs runs periodic background work next to the HTTP server.
+package jobs
+
+import (
+	"context"
+	"denet/internal/lib/logger/sl"
+	"log/slog"
+	"time"
+)
+
+// Job is a unit of periodic work.
+type Job func(ctx context.Context) error
+
+// Run calls job every interval until ctx is cancelled. Failures are logged
+// and retried on the next tick. A non-positive interval disables the job.
+func Run(ctx context.Context, log *slog.Logger, name string, interval time.Duration, job Job) {
+	log = log.With(slog.String("job", name))
+
+	if interval <= 0 {
+		log.Info("job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job(ctx); err != nil {
+			log.Error("job failed", sl.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"denet/internal/lib/models"
+	"log/slog"
+)
+
+type BalanceReconciler interface {
+	ReconcileBalances(repair bool) (*models.ReconcileReport, error)
+}
+
+// Reconcile compares balances with the ledger and logs every mismatch,
+// repairing them when repair is set.
+func Reconcile(log *slog.Logger, reconciler BalanceReconciler, repair bool) Job {
+	return func(ctx context.Context) error {
+		report, err := reconciler.ReconcileBalances(repair)
+		if report != nil {
+			LogReport(log, report)
+		}
+		return err
+	}
+}
+
+// LogReport writes one structured record per mismatch and a summary.
+func LogReport(log *slog.Logger, report *models.ReconcileReport) {
+	var repaired int
+	for _, m := range report.Mismatches {
+		if m.Repaired {
+			repaired++
+		}
+		log.Warn("balance mismatch",
+			slog.Int64("user_id", m.UserId),
+			slog.String("username", m.Username),
+			slog.Int64("balance", m.Balance),
+			slog.Int64("ledger_sum", m.LedgerSum),
+			slog.Int64("difference", m.Difference),
+			slog.Bool("repaired", m.Repaired),
+		)
+	}
+	log.Info("reconciliation finished",
+		slog.Int64("users", report.Users),
+		slog.Int("mismatches", len(report.Mismatches)),
+		slog.Int("repaired", repaired),
+	)
+}
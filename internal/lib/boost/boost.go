@@ -0,0 +1,99 @@
+package boost
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// Rule multiplies task rewards while it is active. Empty Tasks or Tiers
+// match every task or user tier. StartsAt and EndsAt are optional; EndsAt is
+// exclusive.
+type Rule struct {
+	Name       string     `json:"name"`
+	Multiplier float64    `json:"multiplier"`
+	StartsAt   *time.Time `json:"starts_at,omitempty"`
+	EndsAt     *time.Time `json:"ends_at,omitempty"`
+	Tasks      []string   `json:"tasks,omitempty"`
+	Tiers      []string   `json:"tiers,omitempty"`
+}
+
+// ActiveAt reports whether now falls into the rule's time window.
+func (r Rule) ActiveAt(now time.Time) bool {
+	if r.StartsAt != nil && now.Before(*r.StartsAt) {
+		return false
+	}
+	if r.EndsAt != nil && !now.Before(*r.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// Matches reports whether the rule applies to a completion of task by a user
+// of tier at now.
+func (r Rule) Matches(task string, tier string, now time.Time) bool {
+	if !r.ActiveAt(now) {
+		return false
+	}
+	if len(r.Tasks) > 0 && !slices.Contains(r.Tasks, task) {
+		return false
+	}
+	if len(r.Tiers) > 0 && !slices.Contains(r.Tiers, tier) {
+		return false
+	}
+	return true
+}
+
+// Rules is the set of configured boosts.
+type Rules []Rule
+
+// Validate checks that rules are named uniquely, have positive multipliers
+// and sensible windows.
+func (rs Rules) Validate() error {
+	seen := make(map[string]bool, len(rs))
+	for i, r := range rs {
+		switch {
+		case r.Name == "":
+			return fmt.Errorf("boost #%d: name is required", i+1)
+		case seen[r.Name]:
+			return fmt.Errorf("boost %q: duplicate name", r.Name)
+		case r.Multiplier <= 0:
+			return fmt.Errorf("boost %q: multiplier must be positive", r.Name)
+		case r.StartsAt != nil && r.EndsAt != nil && !r.StartsAt.Before(*r.EndsAt):
+			return fmt.Errorf("boost %q: starts_at must be before ends_at", r.Name)
+		}
+		seen[r.Name] = true
+	}
+	return nil
+}
+
+// Active returns the rules whose time window contains now.
+func (rs Rules) Active(now time.Time) []Rule {
+	active := []Rule{}
+	for _, r := range rs {
+		if r.ActiveAt(now) {
+			active = append(active, r)
+		}
+	}
+	return active
+}
+
+// Apply multiplies base by every rule matching the completion. Matching
+// rules stack. The result is rounded down and the names of the applied rules
+// are returned with it.
+func (rs Rules) Apply(base int64, task string, tier string, now time.Time) (int64, []string) {
+	multiplier := 1.0
+	var applied []string
+	for _, r := range rs {
+		if r.Matches(task, tier, now) {
+			multiplier *= r.Multiplier
+			applied = append(applied, r.Name)
+		}
+	}
+	if len(applied) == 0 {
+		return base, nil
+	}
+	// The epsilon keeps products like 100 * 0.29 from rounding down a point.
+	return int64(math.Floor(float64(base)*multiplier + 1e-9)), applied
+}
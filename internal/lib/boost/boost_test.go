@@ -0,0 +1,143 @@
+package boost
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+var (
+	start = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end   = time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+)
+
+func TestApply(t *testing.T) {
+	rules := Rules{
+		{Name: "launch", Multiplier: 2, StartsAt: &start, EndsAt: &end},
+		{Name: "gold", Multiplier: 1.5, Tiers: []string{"gold"}},
+		{Name: "social", Multiplier: 3, Tasks: []string{"follow_twitter", "join_telegram"}},
+	}
+	during := start.Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		rules       Rules
+		base        int64
+		task        string
+		tier        string
+		now         time.Time
+		want        int64
+		wantApplied []string
+	}{
+		{name: "no rules", base: 100, task: "daily", tier: "bronze", now: during, want: 100},
+		{name: "window only", rules: rules, base: 100, task: "daily", tier: "bronze", now: during, want: 200, wantApplied: []string{"launch"}},
+		{name: "stacked", rules: rules, base: 100, task: "daily", tier: "gold", now: during, want: 300, wantApplied: []string{"launch", "gold"}},
+		{name: "all stacked", rules: rules, base: 10, task: "join_telegram", tier: "gold", now: during, want: 90, wantApplied: []string{"launch", "gold", "social"}},
+		{name: "task filter", rules: rules, base: 100, task: "follow_twitter", tier: "bronze", now: end, want: 300, wantApplied: []string{"social"}},
+		{name: "tier filter", rules: rules, base: 100, task: "daily", tier: "silver", now: end, want: 100},
+		{name: "before start", rules: rules, base: 100, task: "daily", tier: "bronze", now: start.Add(-time.Nanosecond), want: 100},
+		{name: "at start", rules: rules, base: 100, task: "daily", tier: "bronze", now: start, want: 200, wantApplied: []string{"launch"}},
+		{name: "just before end", rules: rules, base: 100, task: "daily", tier: "bronze", now: end.Add(-time.Nanosecond), want: 200, wantApplied: []string{"launch"}},
+		{name: "end is exclusive", rules: rules, base: 100, task: "daily", tier: "bronze", now: end, want: 100},
+		{name: "no float rounding loss", rules: Rules{{Name: "cut", Multiplier: 0.29}}, base: 100, task: "daily", tier: "bronze", now: during, want: 29, wantApplied: []string{"cut"}},
+		{name: "rounded down", rules: Rules{{Name: "half", Multiplier: 0.5}}, base: 3, task: "daily", tier: "bronze", now: during, want: 1, wantApplied: []string{"half"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, applied := tt.rules.Apply(tt.base, tt.task, tt.tier, tt.now)
+			if got != tt.want {
+				t.Errorf("points = %d, want %d", got, tt.want)
+			}
+			if !slices.Equal(applied, tt.wantApplied) {
+				t.Errorf("applied = %q, want %q", applied, tt.wantApplied)
+			}
+		})
+	}
+}
+
+func TestActive(t *testing.T) {
+	rules := Rules{
+		{Name: "launch", Multiplier: 2, StartsAt: &start, EndsAt: &end},
+		{Name: "later", Multiplier: 2, StartsAt: &end},
+		{Name: "always", Multiplier: 2},
+	}
+
+	tests := []struct {
+		now  time.Time
+		want []string
+	}{
+		{now: start.Add(-time.Hour), want: []string{"always"}},
+		{now: start, want: []string{"launch", "always"}},
+		{now: end, want: []string{"later", "always"}},
+	}
+	for _, tt := range tests {
+		var names []string
+		for _, r := range rules.Active(tt.now) {
+			names = append(names, r.Name)
+		}
+		if !slices.Equal(names, tt.want) {
+			t.Errorf("Active(%s) = %q, want %q", tt.now, names, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   Rules
+		wantErr string
+	}{
+		{name: "empty"},
+		{
+			name: "valid",
+			rules: Rules{
+				{Name: "launch", Multiplier: 2, StartsAt: &start, EndsAt: &end},
+				{Name: "open", Multiplier: 0.5, StartsAt: &start},
+			},
+		},
+		{
+			name:    "missing name",
+			rules:   Rules{{Name: "launch", Multiplier: 2}, {Multiplier: 2}},
+			wantErr: "boost #2: name is required",
+		},
+		{
+			name:    "duplicate name",
+			rules:   Rules{{Name: "launch", Multiplier: 2}, {Name: "launch", Multiplier: 3}},
+			wantErr: `boost "launch": duplicate name`,
+		},
+		{
+			name:    "zero multiplier",
+			rules:   Rules{{Name: "launch"}},
+			wantErr: `boost "launch": multiplier must be positive`,
+		},
+		{
+			name:    "negative multiplier",
+			rules:   Rules{{Name: "launch", Multiplier: -1}},
+			wantErr: `boost "launch": multiplier must be positive`,
+		},
+		{
+			name:    "empty window",
+			rules:   Rules{{Name: "launch", Multiplier: 2, StartsAt: &end, EndsAt: &end}},
+			wantErr: `boost "launch": starts_at must be before ends_at`,
+		},
+		{
+			name:    "reversed window",
+			rules:   Rules{{Name: "launch", Multiplier: 2, StartsAt: &end, EndsAt: &start}},
+			wantErr: `boost "launch": starts_at must be before ends_at`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rules.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
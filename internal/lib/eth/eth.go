@@ -0,0 +1,70 @@
+// Package eth holds the few Ethereum primitives the service needs to hand
+// out on-chain rewards: Keccak-256 hashing and account addresses.
+package eth
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+var ErrInvalidAddress = errors.New("invalid address")
+
+// Keccak256 returns the legacy Keccak-256 hash of the concatenated data, as
+// used by the EVM.
+func Keccak256(data ...[]byte) [32]byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, b := range data {
+		h.Write(b)
+	}
+	var sum [32]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// Address is a 20 byte account address.
+type Address [20]byte
+
+// ParseAddress parses a 0x-prefixed hex address. Mixed-case addresses must
+// carry a valid EIP-55 checksum.
+func ParseAddress(s string) (Address, error) {
+	var addr Address
+	if len(s) != 42 || !strings.HasPrefix(s, "0x") {
+		return addr, ErrInvalidAddress
+	}
+	if _, err := hex.Decode(addr[:], []byte(s[2:])); err != nil {
+		return addr, ErrInvalidAddress
+	}
+	digits := s[2:]
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && addr.Hex() != s {
+		return addr, ErrInvalidAddress
+	}
+	return addr, nil
+}
+
+// Hex returns the EIP-55 checksummed form of the address.
+func (a Address) Hex() string {
+	digits := []byte(hex.EncodeToString(a[:]))
+	hash := Keccak256(digits)
+	for i, c := range digits {
+		if c < 'a' {
+			continue
+		}
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if nibble >= 8 {
+			digits[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(digits)
+}
+
+// String returns the lowercase form of the address, which is how addresses
+// are stored.
+func (a Address) String() string {
+	return "0x" + hex.EncodeToString(a[:])
+}
@@ -0,0 +1,74 @@
+package eth
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestKeccak256(t *testing.T) {
+	tests := []struct {
+		data []string
+		want string
+	}{
+		{data: nil, want: "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{data: []string{"abc"}, want: "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		{data: []string{"a", "bc"}, want: "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+	}
+	for _, tt := range tests {
+		var data [][]byte
+		for _, s := range tt.data {
+			data = append(data, []byte(s))
+		}
+		sum := Keccak256(data...)
+		if got := hex.EncodeToString(sum[:]); got != tt.want {
+			t.Errorf("Keccak256(%q) = %s, want %s", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestAddressChecksum(t *testing.T) {
+	// Examples from EIP-55.
+	for _, want := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		addr, err := ParseAddress(strings.ToLower(want))
+		if err != nil {
+			t.Fatalf("ParseAddress(%s): %v", want, err)
+		}
+		if got := addr.Hex(); got != want {
+			t.Errorf("Hex() = %s, want %s", got, want)
+		}
+		if got := addr.String(); got != strings.ToLower(want) {
+			t.Errorf("String() = %s, want %s", got, strings.ToLower(want))
+		}
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		{in: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{in: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+		{in: "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED"},
+		{in: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", wantErr: true},
+		{in: "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", wantErr: true},
+		{in: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", wantErr: true},
+		{in: "0xZaAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", wantErr: true},
+	}
+	for _, tt := range tests {
+		_, err := ParseAddress(tt.in)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("ParseAddress(%s) error = %v, want error %v", tt.in, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidAddress) {
+			t.Errorf("ParseAddress(%s) error = %v, want ErrInvalidAddress", tt.in, err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package eth
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// PersonalMessageHash returns the EIP-191 hash that personal_sign signs for
+// msg.
+func PersonalMessageHash(msg []byte) [32]byte {
+	prefix := "\x19Ethereum Signed Message:\n" + strconv.Itoa(len(msg))
+	return Keccak256([]byte(prefix), msg)
+}
+
+// RecoverPersonal returns the address whose key produced sig, a 65 byte
+// r || s || v personal_sign signature of msg. v may be 0/1 or 27/28.
+func RecoverPersonal(msg []byte, sig []byte) (Address, error) {
+	var addr Address
+	if len(sig) != 65 {
+		return addr, ErrInvalidSignature
+	}
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return addr, ErrInvalidSignature
+	}
+
+	// The compact format puts the recovery code first.
+	compact := make([]byte, 65)
+	compact[0] = 27 + v
+	copy(compact[1:], sig[:64])
+
+	hash := PersonalMessageHash(msg)
+	pub, _, err := ecdsa.RecoverCompact(compact, hash[:])
+	if err != nil {
+		return addr, ErrInvalidSignature
+	}
+
+	key := Keccak256(pub.SerializeUncompressed()[1:])
+	copy(addr[:], key[12:])
+	return addr, nil
+}
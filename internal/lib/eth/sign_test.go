@@ -0,0 +1,102 @@
+package eth
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// The personal_sign vector from the web3.js accounts.sign documentation.
+const (
+	vectorKey     = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	vectorMessage = "Some data"
+	vectorHash    = "1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655"
+	vectorSig     = "b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c"
+	vectorAddress = "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"
+)
+
+func mustDecode(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decode %q: %v", s, err)
+	}
+	return b
+}
+
+func TestPersonalMessageHash(t *testing.T) {
+	hash := PersonalMessageHash([]byte(vectorMessage))
+	if got := hex.EncodeToString(hash[:]); got != vectorHash {
+		t.Errorf("PersonalMessageHash = %s, want %s", got, vectorHash)
+	}
+}
+
+func TestVectorKey(t *testing.T) {
+	priv := secp256k1.PrivKeyFromBytes(mustDecode(t, vectorKey))
+	key := Keccak256(priv.PubKey().SerializeUncompressed()[1:])
+
+	var addr Address
+	copy(addr[:], key[12:])
+	if addr.Hex() != vectorAddress {
+		t.Errorf("address of key = %s, want %s", addr.Hex(), vectorAddress)
+	}
+}
+
+func TestRecoverPersonal(t *testing.T) {
+	sig := mustDecode(t, vectorSig)
+	withV := func(v byte) []byte {
+		s := append([]byte(nil), sig...)
+		s[64] = v
+		return s
+	}
+	tampered := withV(sig[64])
+	tampered[40] ^= 0x01
+
+	tests := []struct {
+		name    string
+		msg     string
+		sig     []byte
+		wantErr bool
+		wantNot bool
+	}{
+		{name: "v 28", msg: vectorMessage, sig: sig},
+		{name: "v 1", msg: vectorMessage, sig: withV(1)},
+		{name: "other parity 27", msg: vectorMessage, sig: withV(27), wantNot: true},
+		{name: "other parity 0", msg: vectorMessage, sig: withV(0), wantNot: true},
+		{name: "v 29", msg: vectorMessage, sig: withV(29), wantErr: true},
+		{name: "v 2", msg: vectorMessage, sig: withV(2), wantErr: true},
+		{name: "64 bytes", msg: vectorMessage, sig: sig[:64], wantErr: true},
+		{name: "66 bytes", msg: vectorMessage, sig: append(withV(28), 0), wantErr: true},
+		{name: "empty", msg: vectorMessage, sig: nil, wantErr: true},
+		{name: "tampered signature", msg: vectorMessage, sig: tampered, wantNot: true},
+		{name: "other message", msg: "Some data!", sig: sig, wantNot: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := RecoverPersonal([]byte(tt.msg), tt.sig)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSignature) {
+					t.Fatalf("error = %v, want ErrInvalidSignature", err)
+				}
+				return
+			}
+			if tt.wantNot {
+				// A modified signature either fails to recover or recovers
+				// some other key.
+				if err == nil && addr.Hex() == vectorAddress {
+					t.Fatalf("recovered the signer from a modified signature")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RecoverPersonal: %v", err)
+			}
+			if addr.Hex() != vectorAddress {
+				t.Errorf("recovered %s, want %s", addr.Hex(), vectorAddress)
+			}
+		})
+	}
+}
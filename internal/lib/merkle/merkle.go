@@ -0,0 +1,95 @@
+// Package merkle builds Keccak-256 Merkle trees compatible with the
+// MerkleDistributor airdrop contract and OpenZeppelin's MerkleProof: pairs
+// are sorted before hashing and an odd node is carried up unchanged.
+package merkle
+
+import (
+	"bytes"
+	"denet/internal/lib/eth"
+	"math/big"
+	"slices"
+)
+
+type Hash = [32]byte
+
+// Leaf hashes a claim as keccak256(abi.encodePacked(uint256 index, address
+// account, uint256 amount)).
+func Leaf(index uint64, account eth.Address, amount *big.Int) Hash {
+	var packed [32 + 20 + 32]byte
+	new(big.Int).SetUint64(index).FillBytes(packed[:32])
+	copy(packed[32:52], account[:])
+	amount.FillBytes(packed[52:])
+	return eth.Keccak256(packed[:])
+}
+
+// Tree is a Merkle tree over a set of leaves. Leaves are sorted, so the
+// root does not depend on the order they were passed in.
+type Tree struct {
+	layers [][]Hash
+}
+
+// New builds a tree over leaves. It panics when leaves is empty.
+func New(leaves []Hash) *Tree {
+	if len(leaves) == 0 {
+		panic("merkle: no leaves")
+	}
+
+	layer := slices.Clone(leaves)
+	slices.SortFunc(layer, func(a, b Hash) int { return bytes.Compare(a[:], b[:]) })
+	layer = slices.Compact(layer)
+
+	layers := [][]Hash{layer}
+	for len(layer) > 1 {
+		next := make([]Hash, 0, (len(layer)+1)/2)
+		for i := 0; i < len(layer); i += 2 {
+			if i+1 == len(layer) {
+				next = append(next, layer[i])
+				continue
+			}
+			next = append(next, hashPair(layer[i], layer[i+1]))
+		}
+		layers = append(layers, next)
+		layer = next
+	}
+	return &Tree{layers: layers}
+}
+
+// Root returns the root hash of the tree.
+func (t *Tree) Root() Hash {
+	return t.layers[len(t.layers)-1][0]
+}
+
+// Proof returns the sibling hashes from leaf up to the root, or false when
+// leaf is not in the tree.
+func (t *Tree) Proof(leaf Hash) ([]Hash, bool) {
+	i, ok := slices.BinarySearchFunc(t.layers[0], leaf, func(a, b Hash) int { return bytes.Compare(a[:], b[:]) })
+	if !ok {
+		return nil, false
+	}
+
+	proof := []Hash{}
+	for _, layer := range t.layers[:len(t.layers)-1] {
+		sibling := i ^ 1
+		if sibling < len(layer) {
+			proof = append(proof, layer[sibling])
+		}
+		i /= 2
+	}
+	return proof, true
+}
+
+// Verify reports whether proof links leaf to root.
+func Verify(root Hash, leaf Hash, proof []Hash) bool {
+	h := leaf
+	for _, sibling := range proof {
+		h = hashPair(h, sibling)
+	}
+	return h == root
+}
+
+func hashPair(a, b Hash) Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return eth.Keccak256(a[:], b[:])
+}
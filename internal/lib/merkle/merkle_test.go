@@ -0,0 +1,145 @@
+package merkle
+
+import (
+	"denet/internal/lib/eth"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+// The vectors below follow Uniswap's parse-balance-map BalanceTree: leaves
+// are keccak256(uint256 index, address account, uint256 amount), pairs are
+// sorted and an odd node is carried up. They were computed with a separate
+// reference implementation, not with this package.
+var accounts = []string{
+	"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+	"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+	"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC",
+	"0x90F79bf6EB2c4f870365E785982E1f101E93b906",
+}
+
+var amounts = []int64{200, 300, 250, 100}
+
+var wantLeaves = []string{
+	"6d69d71f3d614803bc489e7b3d5ca668cc948e7180acb76dbef60ba21c504ef2",
+	"0782528e118c4350a2465fbeabec5e72fff06991a29f21c08d37a0d275e38ddd",
+	"6c802e2554072a8319a97d4b9ca8ef01e5976fb5f7ac5ce77e350234bb88e55d",
+	"5cedaa65243af25b467a6e175ca17d921dda21cc3d4d6a4cf7f0dd9bfa30c16e",
+}
+
+func mustHash(t *testing.T, s string) Hash {
+	t.Helper()
+
+	var h Hash
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != len(h) {
+		t.Fatalf("bad hash %q", s)
+	}
+	copy(h[:], b)
+	return h
+}
+
+func leaves(t *testing.T, n int) []Hash {
+	t.Helper()
+
+	out := make([]Hash, n)
+	for i := range n {
+		account, err := eth.ParseAddress(accounts[i])
+		if err != nil {
+			t.Fatalf("parse %s: %v", accounts[i], err)
+		}
+		out[i] = Leaf(uint64(i), account, big.NewInt(amounts[i]))
+	}
+	return out
+}
+
+func TestLeaf(t *testing.T) {
+	for i, leaf := range leaves(t, len(accounts)) {
+		if want := mustHash(t, wantLeaves[i]); leaf != want {
+			t.Errorf("leaf %d = %x, want %x", i, leaf, want)
+		}
+	}
+}
+
+func TestTree(t *testing.T) {
+	tests := []struct {
+		name   string
+		leaves int
+		root   string
+		proofs [][]string
+	}{
+		{
+			name:   "three leaves",
+			leaves: 3,
+			root:   "dc6003573095d3cf8a415ad0ecfcf1793e2a54b2f1c09fd569fe3d3ae6db4fc0",
+			proofs: [][]string{
+				// The odd leaf is carried up and only needs the other pair.
+				{"807bafe3a58f4bcaf449487e49505a27c57e13946dfc2e697eeadec6a1b1e9fd"},
+				{wantLeaves[2], wantLeaves[0]},
+				{wantLeaves[1], wantLeaves[0]},
+			},
+		},
+		{
+			name:   "four leaves",
+			leaves: 4,
+			root:   "a90ee9736c3af0951cdebe93bf53be9808ff7d690aadd2a8ced873d0277bb569",
+			proofs: [][]string{
+				{wantLeaves[2], "cbe5da38b66400ab2de7e8440e674f7499596a662262da59e78e2a4a12ea2f46"},
+				{wantLeaves[3], "3ca755c2b4376db14e5f7e5573721e33ca12485a033e26c79fadbe77cc86c123"},
+				{wantLeaves[0], "cbe5da38b66400ab2de7e8440e674f7499596a662262da59e78e2a4a12ea2f46"},
+				{wantLeaves[1], "3ca755c2b4376db14e5f7e5573721e33ca12485a033e26c79fadbe77cc86c123"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ls := leaves(t, tt.leaves)
+			tree := New(ls)
+
+			root := tree.Root()
+			if want := mustHash(t, tt.root); root != want {
+				t.Fatalf("root = %x, want %x", root, want)
+			}
+			for i, leaf := range ls {
+				proof, ok := tree.Proof(leaf)
+				if !ok {
+					t.Fatalf("leaf %d: no proof", i)
+				}
+				if len(proof) != len(tt.proofs[i]) {
+					t.Fatalf("leaf %d: proof has %d hashes, want %d", i, len(proof), len(tt.proofs[i]))
+				}
+				for j, h := range proof {
+					if want := mustHash(t, tt.proofs[i][j]); h != want {
+						t.Errorf("leaf %d: proof[%d] = %x, want %x", i, j, h, want)
+					}
+				}
+				if !Verify(root, leaf, proof) {
+					t.Errorf("leaf %d: proof does not verify", i)
+				}
+			}
+		})
+	}
+}
+
+func TestTreeOrderAndDuplicates(t *testing.T) {
+	ls := leaves(t, 3)
+	want := New(ls).Root()
+
+	shuffled := []Hash{ls[2], ls[0], ls[1], ls[0]}
+	if got := New(shuffled).Root(); got != want {
+		t.Errorf("root of shuffled leaves = %x, want %x", got, want)
+	}
+}
+
+func TestProofUnknownLeaf(t *testing.T) {
+	ls := leaves(t, 4)
+	tree := New(ls[:3])
+
+	if _, ok := tree.Proof(ls[3]); ok {
+		t.Error("Proof returned a proof for a leaf outside the tree")
+	}
+	proof, _ := tree.Proof(ls[0])
+	if Verify(tree.Root(), ls[3], proof) {
+		t.Error("Verify accepted a leaf outside the tree")
+	}
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"denet/internal/lib/recurrence"
+	"time"
+)
 
 type User struct {
 	Id          int64     `json:"id"`
@@ -9,4 +12,334 @@ type User struct {
 	Points      int64     `json:"points"`
 	Referral_id int64     `json:"referral_id"`
 	Created_at  time.Time `json:"created_at"`
+	IsAdmin     bool      `json:"is_admin"`
+	Tier        string    `json:"tier,omitempty"`
+	Wallet      string    `json:"wallet_address,omitempty"`
+}
+
+type Task struct {
+	Id               int64      `json:"id"`
+	Slug             string     `json:"slug"`
+	Title            string     `json:"title"`
+	Type             string     `json:"type"`
+	Target           string     `json:"target,omitempty"`
+	Reward           int64      `json:"reward"`
+	Active           bool       `json:"active"`
+	Recurrence       string     `json:"recurrence"`
+	CooldownSeconds  int64      `json:"cooldown_seconds,omitempty"`
+	StreakBonus      int64      `json:"streak_bonus,omitempty"`
+	StartsAt         *time.Time `json:"starts_at,omitempty"`
+	EndsAt           *time.Time `json:"ends_at,omitempty"`
+	MaxCompletions   int64      `json:"max_completions,omitempty"` // 0 means unlimited
+	CompletionsCount int64      `json:"completions_count"`
+	Counter          string     `json:"counter,omitempty"`
+	TargetValue      int64      `json:"target_value,omitempty"`
+	ExpiryDays       int64      `json:"expiry_days,omitempty"` // 0 means never
+	// Rewards holds amounts of currencies other than points granted on
+	// completion. Reward is always paid in points.
+	Rewards map[string]int64 `json:"rewards,omitempty"`
+}
+
+func (t Task) Policy() recurrence.Policy {
+	return recurrence.Policy{
+		Kind:     t.Recurrence,
+		Cooldown: time.Duration(t.CooldownSeconds) * time.Second,
+	}
+}
+
+// Counters that progress-based tasks can track.
+const (
+	CounterReferrals = "referrals"
+	CounterCheckIns  = "check_ins"
+)
+
+// ProgressBased reports whether the task completes automatically once its
+// counter reaches TargetValue.
+func (t Task) ProgressBased() bool {
+	return t.TargetValue > 0
+}
+
+// Window reports whether the task can be completed at now according to its
+// availability window, returning TaskUpcoming or TaskExpired otherwise.
+func (t Task) Window(now time.Time) (string, bool) {
+	if t.StartsAt != nil && now.Before(*t.StartsAt) {
+		return TaskUpcoming, false
+	}
+	if t.EndsAt != nil && !now.Before(*t.EndsAt) {
+		return TaskExpired, false
+	}
+	return "", true
+}
+
+// Completion is the outcome of a successfully completed task. Points
+// includes boosts; Base is the reward plus streak bonus before them.
+type Completion struct {
+	Points          int64            `json:"points"`
+	Base            int64            `json:"base"`
+	Bonus           int64            `json:"bonus"`
+	Streak          int64            `json:"streak"`
+	NextAvailableAt time.Time        `json:"next_available_at"`
+	Rewards         map[string]int64 `json:"rewards,omitempty"`
+	Boosts          []string         `json:"boosts,omitempty"`
+}
+
+const (
+	SubmissionPending  = "pending"
+	SubmissionApproved = "approved"
+	SubmissionRejected = "rejected"
+)
+
+// Submission is a proof sent for a manually moderated task.
+type Submission struct {
+	Id         int64      `json:"id"`
+	UserId     int64      `json:"user_id"`
+	Username   string     `json:"username,omitempty"`
+	TaskId     int64      `json:"task_id"`
+	TaskSlug   string     `json:"task_slug"`
+	ProofURL   string     `json:"proof_url,omitempty"`
+	ProofText  string     `json:"proof_text,omitempty"`
+	Status     string     `json:"status"`
+	Reason     string     `json:"reason,omitempty"`
+	ReviewedBy string     `json:"reviewed_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+}
+
+const (
+	TaskAvailable = "available"
+	TaskCompleted = "completed"
+	TaskCooldown  = "cooldown"
+	TaskLocked    = "locked"
+	TaskPending   = "pending"
+	TaskUpcoming  = "upcoming"
+	TaskExpired   = "expired"
+	TaskExhausted = "exhausted"
+)
+
+// UserTask is a catalog task together with one user's progress on it.
+type UserTask struct {
+	Task
+	State                string     `json:"state"`
+	Prerequisites        []string   `json:"prerequisites,omitempty"`
+	MissingPrerequisites []string   `json:"missing_prerequisites,omitempty"`
+	Completions          int64      `json:"completions"`
+	Streak               int64      `json:"streak"`
+	LastCompletedAt      *time.Time `json:"last_completed_at,omitempty"`
+	AvailableAt          *time.Time `json:"available_at,omitempty"`
+	Progress             *Progress  `json:"progress,omitempty"`
+}
+
+// Progress is a user's counter on a progress-based task.
+type Progress struct {
+	TaskId      int64      `json:"task_id"`
+	TaskSlug    string     `json:"task_slug"`
+	Current     int64      `json:"current"`
+	Target      int64      `json:"target"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// CatalogTask is a catalog task with the slugs of its prerequisites.
+type CatalogTask struct {
+	Task
+	Prerequisites []string `json:"prerequisites,omitempty"`
+}
+
+// CurrencyPoints is the spendable currency kept in users.points. Transfers,
+// redemptions and expiry only apply to points.
+const CurrencyPoints = "points"
+
+// Currency is a named balance type configured for the deployment.
+type Currency struct {
+	Code  string `json:"code"`
+	Title string `json:"title"`
+}
+
+// LeaderboardEntry is a user ranked by their balance in one currency.
+type LeaderboardEntry struct {
+	User
+	Balance int64 `json:"balance"`
+}
+
+// Reasons recorded on ledger entries.
+const (
+	ReasonOpening    = "opening"
+	ReasonSignup     = "signup"
+	ReasonTask       = "task"
+	ReasonReferral   = "referral"
+	ReasonAdmin      = "admin"
+	ReasonTransfer   = "transfer"
+	ReasonRedemption = "redemption"
+	ReasonExpiry     = "expiry"
+	ReasonCorrection = "correction"
+)
+
+// LedgerEntry is a single balance change in the points ledger.
+// CounterpartyId is the other side of a transfer. ExpiresAt is set on credits
+// that lapse when not spent in time. Actor is the admin who posted a manual
+// adjustment. BaseDelta is the amount before boosts and is only set when
+// Boosts were applied.
+type LedgerEntry struct {
+	Id             int64      `json:"id"`
+	UserId         int64      `json:"user_id"`
+	Currency       string     `json:"currency"`
+	Delta          int64      `json:"delta"`
+	Reason         string     `json:"reason"`
+	TaskId         *int64     `json:"task_id,omitempty"`
+	ReferralId     *int64     `json:"referral_id,omitempty"`
+	CounterpartyId *int64     `json:"counterparty_id,omitempty"`
+	RedemptionId   *int64     `json:"redemption_id,omitempty"`
+	Memo           string     `json:"memo,omitempty"`
+	Actor          string     `json:"actor,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	BaseDelta      *int64     `json:"base_delta,omitempty"`
+	Boosts         []string   `json:"boosts,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// HistoryEntry is a ledger entry with the user's balance right after it.
+type HistoryEntry struct {
+	LedgerEntry
+	Balance int64 `json:"balance"`
+}
+
+// HistoryFilter selects a page of a user's ledger, newest first. Cursor is
+// the ID of the last entry of the previous page.
+type HistoryFilter struct {
+	Cursor   int64
+	Limit    int
+	Reason   string
+	Currency string
+	From     *time.Time
+	To       *time.Time
+}
+
+// Transfer is a completed gift of points from one user to another.
+type Transfer struct {
+	Id        int64     `json:"id"`
+	FromId    int64     `json:"from_id"`
+	ToId      int64     `json:"to_id"`
+	Amount    int64     `json:"amount"`
+	Memo      string    `json:"memo,omitempty"`
+	Balance   int64     `json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TransferLimits caps what a user may send per UTC day. Zero disables a
+// limit.
+type TransferLimits struct {
+	DailyAmount int64
+	DailyCount  int64
+}
+
+// Reward is an item of the rewards shop that users buy with points. Rewards
+// with CodePool set hand out one uploaded voucher code per redemption.
+type Reward struct {
+	Id          int64     `json:"id"`
+	Slug        string    `json:"slug"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Price       int64     `json:"price"`
+	Stock       int64     `json:"stock"`
+	Active      bool      `json:"active"`
+	CodePool    bool      `json:"code_pool"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+const (
+	RedemptionPending   = "pending"
+	RedemptionFulfilled = "fulfilled"
+	RedemptionCancelled = "cancelled"
+)
+
+// Redemption is a reward bought by a user. Price is what the user paid at
+// the time of purchase.
+type Redemption struct {
+	Id          int64      `json:"id"`
+	UserId      int64      `json:"user_id"`
+	Username    string     `json:"username,omitempty"`
+	RewardId    int64      `json:"reward_id"`
+	RewardSlug  string     `json:"reward_slug"`
+	RewardTitle string     `json:"reward_title"`
+	Price       int64      `json:"price"`
+	Code        string     `json:"code,omitempty"`
+	Status      string     `json:"status"`
+	Note        string     `json:"note,omitempty"`
+	UpdatedBy   string     `json:"updated_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// Snapshot is a point-in-time leaderboard of one currency. TakenAt is the
+// moment the balances were computed for; entries posted later never change
+// it.
+type Snapshot struct {
+	Id        int64           `json:"id"`
+	Currency  string          `json:"currency"`
+	TakenAt   time.Time       `json:"taken_at"`
+	Users     int64           `json:"users"`
+	Total     int64           `json:"total"`
+	CreatedBy string          `json:"created_by"`
+	CreatedAt time.Time       `json:"created_at"`
+	Entries   []SnapshotEntry `json:"entries,omitempty"`
+}
+
+// SnapshotEntry is one user's balance in a snapshot.
+type SnapshotEntry struct {
+	Rank     int64  `json:"rank"`
+	UserId   int64  `json:"user_id"`
+	Username string `json:"username"`
+	Balance  int64  `json:"balance"`
+}
+
+// Airdrop is a Merkle tree of token claims built from a snapshot. Amounts
+// are uint256 values in decimal; the balance of a user is scaled by
+// 10^Decimals.
+type Airdrop struct {
+	Id         int64     `json:"id"`
+	SnapshotId int64     `json:"snapshot_id"`
+	Root       string    `json:"root"`
+	Decimals   int       `json:"decimals"`
+	Claims     int64     `json:"claims"`
+	Total      string    `json:"total"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AirdropClaim is one leaf of an airdrop with its proof.
+type AirdropClaim struct {
+	AirdropId int64    `json:"airdrop_id"`
+	UserId    int64    `json:"user_id"`
+	Index     int64    `json:"index"`
+	Account   string   `json:"account"`
+	Amount    string   `json:"amount"`
+	Proof     []string `json:"proof"`
+	Root      string   `json:"root"`
+}
+
+// WalletChallenge is a message a user must sign with the key of Address to
+// link it. Address is stored lowercase.
+type WalletChallenge struct {
+	UserId    int64     `json:"user_id"`
+	Address   string    `json:"address"`
+	Nonce     string    `json:"nonce"`
+	Message   string    `json:"message"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Mismatch is a user whose points column disagrees with their ledger.
+type Mismatch struct {
+	UserId     int64  `json:"user_id"`
+	Username   string `json:"username"`
+	Balance    int64  `json:"balance"`
+	LedgerSum  int64  `json:"ledger_sum"`
+	Difference int64  `json:"difference"`
+	Repaired   bool   `json:"repaired"`
+	EntryId    int64  `json:"entry_id,omitempty"`
+}
+
+// ReconcileReport is the outcome of comparing balances with the ledger.
+type ReconcileReport struct {
+	CheckedAt  time.Time  `json:"checked_at"`
+	Users      int64      `json:"users"`
+	Mismatches []Mismatch `json:"mismatches"`
 }
@@ -0,0 +1,92 @@
+package recurrence
+
+import "time"
+
+const (
+	Once     = "once"
+	Daily    = "daily"
+	Weekly   = "weekly"
+	Cooldown = "cooldown"
+)
+
+// Policy describes how often a task can be completed by the same user.
+// Daily and weekly periods are aligned to UTC calendar days and ISO weeks.
+type Policy struct {
+	Kind     string
+	Cooldown time.Duration
+}
+
+func (p Policy) Valid() bool {
+	switch p.Kind {
+	case Once, Daily, Weekly:
+		return true
+	case Cooldown:
+		return p.Cooldown > 0
+	}
+	return false
+}
+
+// Repeatable reports whether the task can be completed more than once.
+func (p Policy) Repeatable() bool {
+	return p.Kind != Once
+}
+
+// NextAvailable returns the moment a task completed at last becomes available
+// again. The second result is false for tasks that can never be repeated.
+func (p Policy) NextAvailable(last time.Time) (time.Time, bool) {
+	switch p.Kind {
+	case Daily:
+		return startOfDay(last).AddDate(0, 0, 1), true
+	case Weekly:
+		return startOfWeek(last).AddDate(0, 0, 7), true
+	case Cooldown:
+		return last.Add(p.Cooldown), true
+	}
+	return time.Time{}, false
+}
+
+// deadline returns the end of the window following a completion at last in
+// which the next completion still continues the streak.
+func (p Policy) deadline(last time.Time) time.Time {
+	next, ok := p.NextAvailable(last)
+	if !ok {
+		return time.Time{}
+	}
+	switch p.Kind {
+	case Daily:
+		return next.AddDate(0, 0, 1)
+	case Weekly:
+		return next.AddDate(0, 0, 7)
+	}
+	return next.Add(p.Cooldown)
+}
+
+// Consecutive reports whether a completion at now continues the streak of a
+// completion at last, i.e. it happens in the very next period.
+func (p Policy) Consecutive(last, now time.Time) bool {
+	next, ok := p.NextAvailable(last)
+	if !ok {
+		return false
+	}
+	return !now.Before(next) && now.Before(p.deadline(last))
+}
+
+// StreakAlive reports whether a streak ending with a completion at last can
+// still be continued at now.
+func (p Policy) StreakAlive(last, now time.Time) bool {
+	if !p.Repeatable() {
+		return false
+	}
+	return now.Before(p.deadline(last))
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func startOfWeek(t time.Time) time.Time {
+	day := startOfDay(t)
+	offset := (int(day.Weekday()) + 6) % 7 // Monday is the first day of the week
+	return day.AddDate(0, 0, -offset)
+}
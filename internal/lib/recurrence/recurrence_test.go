@@ -0,0 +1,113 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+func at(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestStartOfWeek(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "2026-03-02T00:00:00Z", want: "2026-03-02T00:00:00Z"}, // Monday
+		{in: "2026-03-04T15:30:00Z", want: "2026-03-02T00:00:00Z"},
+		{in: "2026-03-08T23:59:59Z", want: "2026-03-02T00:00:00Z"}, // Sunday
+		{in: "2026-03-09T00:00:00Z", want: "2026-03-09T00:00:00Z"},
+		{in: "2026-01-01T12:00:00Z", want: "2025-12-29T00:00:00Z"}, // across the year
+		{in: "2024-03-01T08:00:00Z", want: "2024-02-26T00:00:00Z"}, // across a leap day
+		// Monday 01:00 at UTC+3 is still Sunday in UTC.
+		{in: "2026-03-09T01:00:00+03:00", want: "2026-03-02T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		if got := startOfWeek(at(tt.in)); !got.Equal(at(tt.want)) || got.Location() != time.UTC {
+			t.Errorf("startOfWeek(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNextAvailable(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		last   string
+		want   string
+		wantOK bool
+	}{
+		{name: "once", policy: Policy{Kind: Once}, last: "2026-03-04T10:00:00Z"},
+		{name: "daily", policy: Policy{Kind: Daily}, last: "2026-03-04T10:00:00Z", want: "2026-03-05T00:00:00Z", wantOK: true},
+		{name: "daily at midnight", policy: Policy{Kind: Daily}, last: "2026-03-04T00:00:00Z", want: "2026-03-05T00:00:00Z", wantOK: true},
+		{name: "daily before midnight", policy: Policy{Kind: Daily}, last: "2026-03-04T23:59:59Z", want: "2026-03-05T00:00:00Z", wantOK: true},
+		{name: "daily month end", policy: Policy{Kind: Daily}, last: "2026-02-28T18:00:00Z", want: "2026-03-01T00:00:00Z", wantOK: true},
+		{name: "daily leap day", policy: Policy{Kind: Daily}, last: "2024-02-28T18:00:00Z", want: "2024-02-29T00:00:00Z", wantOK: true},
+		{name: "daily year end", policy: Policy{Kind: Daily}, last: "2025-12-31T23:00:00Z", want: "2026-01-01T00:00:00Z", wantOK: true},
+		{name: "daily in UTC", policy: Policy{Kind: Daily}, last: "2026-03-05T01:00:00+03:00", want: "2026-03-05T00:00:00Z", wantOK: true},
+		{name: "weekly", policy: Policy{Kind: Weekly}, last: "2026-03-04T10:00:00Z", want: "2026-03-09T00:00:00Z", wantOK: true},
+		{name: "weekly on Monday", policy: Policy{Kind: Weekly}, last: "2026-03-02T00:00:00Z", want: "2026-03-09T00:00:00Z", wantOK: true},
+		{name: "weekly on Sunday", policy: Policy{Kind: Weekly}, last: "2026-03-08T23:59:59Z", want: "2026-03-09T00:00:00Z", wantOK: true},
+		{name: "weekly year end", policy: Policy{Kind: Weekly}, last: "2025-12-31T10:00:00Z", want: "2026-01-05T00:00:00Z", wantOK: true},
+		{name: "cooldown", policy: Policy{Kind: Cooldown, Cooldown: 36 * time.Hour}, last: "2026-03-04T10:00:00Z", want: "2026-03-05T22:00:00Z", wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.policy.NextAvailable(at(tt.last))
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !got.Equal(at(tt.want)) {
+				t.Errorf("NextAvailable = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsecutiveAndStreakAlive(t *testing.T) {
+	daily := Policy{Kind: Daily}
+	weekly := Policy{Kind: Weekly}
+	cooldown := Policy{Kind: Cooldown, Cooldown: 12 * time.Hour}
+
+	tests := []struct {
+		name            string
+		policy          Policy
+		last            string
+		now             string
+		wantConsecutive bool
+		wantAlive       bool
+	}{
+		{name: "once", policy: Policy{Kind: Once}, last: "2026-03-04T10:00:00Z", now: "2026-03-05T10:00:00Z"},
+		{name: "daily same day", policy: daily, last: "2026-03-04T10:00:00Z", now: "2026-03-04T23:59:59Z", wantAlive: true},
+		{name: "daily next day start", policy: daily, last: "2026-03-04T23:59:59Z", now: "2026-03-05T00:00:00Z", wantConsecutive: true, wantAlive: true},
+		{name: "daily next day end", policy: daily, last: "2026-03-04T00:00:00Z", now: "2026-03-05T23:59:59Z", wantConsecutive: true, wantAlive: true},
+		{name: "daily day skipped", policy: daily, last: "2026-03-04T23:59:59Z", now: "2026-03-06T00:00:00Z"},
+		{name: "daily across month", policy: daily, last: "2026-02-28T12:00:00Z", now: "2026-03-01T12:00:00Z", wantConsecutive: true, wantAlive: true},
+		{name: "daily across year", policy: daily, last: "2025-12-31T12:00:00Z", now: "2026-01-01T12:00:00Z", wantConsecutive: true, wantAlive: true},
+		{name: "weekly same week", policy: weekly, last: "2026-03-02T10:00:00Z", now: "2026-03-08T23:59:59Z", wantAlive: true},
+		{name: "weekly Sunday to Monday", policy: weekly, last: "2026-03-08T23:59:59Z", now: "2026-03-09T00:00:00Z", wantConsecutive: true, wantAlive: true},
+		{name: "weekly Monday to Sunday", policy: weekly, last: "2026-03-02T00:00:00Z", now: "2026-03-15T23:59:59Z", wantConsecutive: true, wantAlive: true},
+		{name: "weekly week skipped", policy: weekly, last: "2026-03-08T23:59:59Z", now: "2026-03-16T00:00:00Z"},
+		{name: "weekly across year", policy: weekly, last: "2025-12-29T10:00:00Z", now: "2026-01-05T10:00:00Z", wantConsecutive: true, wantAlive: true},
+		{name: "cooldown running", policy: cooldown, last: "2026-03-04T10:00:00Z", now: "2026-03-04T21:59:59Z", wantAlive: true},
+		{name: "cooldown over", policy: cooldown, last: "2026-03-04T10:00:00Z", now: "2026-03-04T22:00:00Z", wantConsecutive: true, wantAlive: true},
+		{name: "cooldown window end", policy: cooldown, last: "2026-03-04T10:00:00Z", now: "2026-03-05T09:59:59Z", wantConsecutive: true, wantAlive: true},
+		{name: "cooldown window missed", policy: cooldown, last: "2026-03-04T10:00:00Z", now: "2026-03-05T10:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			last, now := at(tt.last), at(tt.now)
+			if got := tt.policy.Consecutive(last, now); got != tt.wantConsecutive {
+				t.Errorf("Consecutive = %v, want %v", got, tt.wantConsecutive)
+			}
+			if got := tt.policy.StreakAlive(last, now); got != tt.wantAlive {
+				t.Errorf("StreakAlive = %v, want %v", got, tt.wantAlive)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package taskgraph
+
+import "sort"
+
+// FindCycle looks for a cycle in the prerequisite graph, where edges maps a
+// task ID to the IDs of the tasks it depends on. It returns the task IDs that
+// form the cycle, with the first ID repeated at the end, or nil when the graph
+// is acyclic.
+func FindCycle(edges map[int64][]int64) []int64 {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	nodes := make([]int64, 0, len(edges))
+	for id := range edges {
+		nodes = append(nodes, id)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+
+	state := make(map[int64]int, len(edges))
+	var path []int64
+
+	var visit func(id int64) []int64
+	visit = func(id int64) []int64 {
+		state[id] = visiting
+		path = append(path, id)
+		for _, next := range edges[id] {
+			switch state[next] {
+			case visiting:
+				for i, p := range path {
+					if p == next {
+						cycle := append([]int64{}, path[i:]...)
+						return append(cycle, next)
+					}
+				}
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		return nil
+	}
+
+	for _, id := range nodes {
+		if state[id] == unvisited {
+			if cycle := visit(id); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
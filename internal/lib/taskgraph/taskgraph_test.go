@@ -0,0 +1,58 @@
+package taskgraph
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindCycle(t *testing.T) {
+	tests := []struct {
+		name  string
+		edges map[int64][]int64
+		want  []int64
+	}{
+		{name: "empty", edges: map[int64][]int64{}},
+		{name: "self-loop", edges: map[int64][]int64{1: {1}}, want: []int64{1, 1}},
+		{name: "two-cycle", edges: map[int64][]int64{1: {2}, 2: {1}}, want: []int64{1, 2, 1}},
+		{
+			name:  "long cycle",
+			edges: map[int64][]int64{1: {2}, 2: {3}, 3: {4}, 4: {5}, 5: {1}},
+			want:  []int64{1, 2, 3, 4, 5, 1},
+		},
+		{
+			name:  "cycle behind a tail",
+			edges: map[int64][]int64{1: {2}, 2: {3}, 3: {4}, 4: {2}},
+			want:  []int64{2, 3, 4, 2},
+		},
+		{
+			name:  "cycle apart from a DAG",
+			edges: map[int64][]int64{1: {2}, 2: nil, 7: {8}, 8: {9}, 9: {7}},
+			want:  []int64{7, 8, 9, 7},
+		},
+		{
+			name:  "diamond",
+			edges: map[int64][]int64{4: {2, 3}, 2: {1}, 3: {1}, 1: nil},
+		},
+		{
+			name:  "chain with shared dependencies",
+			edges: map[int64][]int64{1: {2, 3, 4}, 2: {3, 4}, 3: {4}, 4: nil},
+		},
+		{
+			name:  "dependency without edges of its own",
+			edges: map[int64][]int64{1: {2}, 3: {2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindCycle(tt.edges)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("FindCycle = %v, want %v", got, tt.want)
+			}
+			for i := 0; i+1 < len(got); i++ {
+				if !slices.Contains(tt.edges[got[i]], got[i+1]) {
+					t.Errorf("%d -> %d is not an edge", got[i], got[i+1])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"database/sql"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// GetWalletAddresses returns the linked wallet of every user that has one.
+func (s *Storage) GetWalletAddresses() (map[int64]string, error) {
+	const op = "storage.postgresql.GetWalletAddresses"
+
+	rows, err := s.db.Query(`SELECT id, wallet_address FROM users WHERE wallet_address IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	wallets := make(map[int64]string)
+	for rows.Next() {
+		var userID int64
+		var wallet string
+		if err := rows.Scan(&userID, &wallet); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		wallets[userID] = wallet
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return wallets, nil
+}
+
+// CreateAirdrop stores an airdrop with all of its claims and sets its Id.
+func (s *Storage) CreateAirdrop(airdrop *models.Airdrop, claims []models.AirdropClaim) error {
+	const op = "storage.postgresql.CreateAirdrop"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO airdrops (snapshot_id, root, decimals, claims, total, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		airdrop.SnapshotId, airdrop.Root, airdrop.Decimals, airdrop.Claims, airdrop.Total, airdrop.CreatedAt,
+	).Scan(&airdrop.Id)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return storage.ErrSnapshotNotFound
+		}
+		return fmt.Errorf("%s: insert airdrop: %w", op, err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO airdrop_claims (airdrop_id, user_id, claim_index, account, amount, proof)
+		VALUES ($1, $2, $3, $4, $5, $6)`)
+	if err != nil {
+		return fmt.Errorf("%s: prepare statement: %w", op, err)
+	}
+	defer stmt.Close()
+
+	for _, claim := range claims {
+		_, err := stmt.Exec(airdrop.Id, claim.UserId, claim.Index, claim.Account, claim.Amount, pq.Array(claim.Proof))
+		if err != nil {
+			return fmt.Errorf("%s: insert claim of user %d: %w", op, claim.UserId, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return nil
+}
+
+// GetAirdropClaim returns the user's claim in an airdrop, or in the latest
+// airdrop when airdropID is 0.
+func (s *Storage) GetAirdropClaim(userID int64, airdropID int64) (*models.AirdropClaim, error) {
+	const op = "storage.postgresql.GetAirdropClaim"
+
+	var root string
+	err := s.db.QueryRow(`
+		SELECT id, root FROM airdrops
+		WHERE $1 = 0 OR id = $1
+		ORDER BY id DESC
+		LIMIT 1`, airdropID,
+	).Scan(&airdropID, &root)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrAirdropNotFound
+		}
+		return nil, fmt.Errorf("%s: select airdrop: %w", op, err)
+	}
+
+	claim := &models.AirdropClaim{AirdropId: airdropID, UserId: userID, Root: root}
+	err = s.db.QueryRow(`
+		SELECT claim_index, account, amount::text, proof
+		FROM airdrop_claims
+		WHERE airdrop_id = $1 AND user_id = $2`, airdropID, userID,
+	).Scan(&claim.Index, &claim.Account, &claim.Amount, pq.Array(&claim.Proof))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrClaimNotFound
+		}
+		return nil, fmt.Errorf("%s: select claim: %w", op, err)
+	}
+	return claim, nil
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"fmt"
+)
+
+// SyncCurrencies upserts the configured currencies. Currencies removed from
+// the config are kept so that existing balances and ledger entries stay
+// valid.
+func (s *Storage) SyncCurrencies(currencies []models.Currency) error {
+	const op = "storage.postgresql.SyncCurrencies"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	for _, currency := range currencies {
+		_, err := tx.Exec(`
+			INSERT INTO currencies (code, title) VALUES ($1, $2)
+			ON CONFLICT (code) DO UPDATE SET title = EXCLUDED.title`, currency.Code, currency.Title)
+		if err != nil {
+			return fmt.Errorf("%s: upsert %q: %w", op, currency.Code, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return nil
+}
+
+// GetUserBalances returns the user's balance in every currency, including
+// currencies the user has never earned.
+func (s *Storage) GetUserBalances(userID int64) (map[string]int64, error) {
+	const op = "storage.postgresql.GetUserBalances"
+
+	rows, err := s.db.Query(`
+		SELECT c.code, CASE WHEN c.code = $2 THEN u.points ELSE COALESCE(b.balance, 0) END
+		FROM users u
+		CROSS JOIN currencies c
+		LEFT JOIN user_balances b ON b.user_id = u.id AND b.currency = c.code
+		WHERE u.id = $1`, userID, models.CurrencyPoints)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	balances := make(map[string]int64)
+	for rows.Next() {
+		var currency string
+		var balance int64
+		if err := rows.Scan(&currency, &balance); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		balances[currency] = balance
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(balances) == 0 {
+		return nil, storage.ErrUserNotFound
+	}
+	return balances, nil
+}
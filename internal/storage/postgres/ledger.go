@@ -0,0 +1,422 @@
+package postgres
+
+import (
+	"database/sql"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// postEntry is the only place where balances change. It applies the entry
+// to the balance of its currency and appends it to the ledger in the
+// caller's transaction. Zero deltas only check that the user exists.
+//
+// Points credits keep track of their unspent remainder so that they can
+// expire. Points debits are charged against the oldest unspent credits first,
+// except for expiry debits, whose credits are settled by the caller.
+func postEntry(tx *sql.Tx, entry models.LedgerEntry) (int64, error) {
+	const op = "storage.postgresql.postEntry"
+
+	if entry.Currency == "" {
+		entry.Currency = models.CurrencyPoints
+	}
+
+	var err error
+	if entry.Currency == models.CurrencyPoints {
+		err = applyPoints(tx, entry)
+	} else {
+		err = applyBalance(tx, entry)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if entry.Delta == 0 {
+		return 0, nil
+	}
+
+	var id int64
+	err = tx.QueryRow(`
+		INSERT INTO points_transactions (user_id, currency, delta, reason, task_id, referral_id, counterparty_id,
+			redemption_id, memo, actor, expires_at, remaining, base_delta, boosts, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11, $12, $13, $14, $15)
+		RETURNING id`,
+		entry.UserId, entry.Currency, entry.Delta, entry.Reason, entry.TaskId, entry.ReferralId, entry.CounterpartyId,
+		entry.RedemptionId, entry.Memo, entry.Actor, entry.ExpiresAt, remainingOf(entry), entry.BaseDelta,
+		pq.Array(entry.Boosts), entry.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: insert entry: %w", op, err)
+	}
+	return id, nil
+}
+
+func applyPoints(tx *sql.Tx, entry models.LedgerEntry) error {
+	const op = "storage.postgresql.applyPoints"
+
+	result, err := tx.Exec(`UPDATE users SET points = points + $1 WHERE id = $2`, entry.Delta, entry.UserId)
+	if err != nil {
+		return fmt.Errorf("%s: update points for user %d: %w", op, entry.UserId, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get affected rows count: %w", op, err)
+	}
+	if rowsAffected == 0 {
+		return storage.ErrUserNotFound
+	}
+
+	if entry.Delta < 0 && entry.Reason != models.ReasonExpiry {
+		if err := spendCredits(tx, entry.UserId, -entry.Delta); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	return nil
+}
+
+func applyBalance(tx *sql.Tx, entry models.LedgerEntry) error {
+	const op = "storage.postgresql.applyBalance"
+
+	_, err := tx.Exec(`
+		INSERT INTO user_balances (user_id, currency, balance)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, currency) DO UPDATE SET balance = user_balances.balance + EXCLUDED.balance`,
+		entry.UserId, entry.Currency, entry.Delta)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			if strings.Contains(pqErr.Constraint, "currency") {
+				return fmt.Errorf("%w: %s", storage.ErrUnknownCurrency, entry.Currency)
+			}
+			return storage.ErrUserNotFound
+		}
+		return fmt.Errorf("%s: update %s for user %d: %w", op, entry.Currency, entry.UserId, err)
+	}
+	return nil
+}
+
+// remainingOf returns the unspent part a new entry starts with. Only points
+// credits are tracked.
+func remainingOf(entry models.LedgerEntry) int64 {
+	if entry.Currency != models.CurrencyPoints || entry.Delta < 0 {
+		return 0
+	}
+	return entry.Delta
+}
+
+// spendCredits takes amount out of the user's unspent credits, oldest first.
+// The caller must hold the lock on the user row.
+func spendCredits(tx *sql.Tx, userID int64, amount int64) error {
+	_, err := tx.Exec(`
+		WITH credits AS (
+			SELECT id, remaining, SUM(remaining) OVER (ORDER BY id) - remaining AS before
+			FROM points_transactions
+			WHERE user_id = $1 AND remaining > 0
+		)
+		UPDATE points_transactions pt
+		SET remaining = pt.remaining - LEAST(credits.remaining, $2 - credits.before)
+		FROM credits
+		WHERE pt.id = credits.id AND credits.before < $2`, userID, amount)
+	if err != nil {
+		return fmt.Errorf("spend credits: %w", err)
+	}
+	return nil
+}
+
+// creditPart is an amount of unspent credits sharing one expiry.
+type creditPart struct {
+	expiresAt *time.Time
+	amount    int64
+}
+
+// creditsToSpend returns what spendCredits would take out of the user's
+// credits for amount, grouped by expiry with the soonest first. Any part of
+// amount not backed by tracked credits is returned without an expiry. The
+// caller must hold the lock on the user row.
+func creditsToSpend(tx *sql.Tx, userID int64, amount int64) ([]creditPart, error) {
+	rows, err := tx.Query(`
+		SELECT expires_at, SUM(LEAST(remaining, $2 - before))::bigint
+		FROM (
+			SELECT remaining, expires_at, SUM(remaining) OVER (ORDER BY id) - remaining AS before
+			FROM points_transactions
+			WHERE user_id = $1 AND remaining > 0
+		) credits
+		WHERE before < $2
+		GROUP BY expires_at
+		ORDER BY expires_at NULLS LAST`, userID, amount)
+	if err != nil {
+		return nil, fmt.Errorf("select credits: %w", err)
+	}
+	defer rows.Close()
+
+	var parts []creditPart
+	var covered int64
+	for rows.Next() {
+		var part creditPart
+		if err := rows.Scan(&part.expiresAt, &part.amount); err != nil {
+			return nil, fmt.Errorf("scan credits: %w", err)
+		}
+		covered += part.amount
+		parts = append(parts, part)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("select credits: %w", err)
+	}
+
+	if covered < amount {
+		n := len(parts)
+		if n > 0 && parts[n-1].expiresAt == nil {
+			parts[n-1].amount += amount - covered
+		} else {
+			parts = append(parts, creditPart{amount: amount - covered})
+		}
+	}
+	return parts, nil
+}
+
+// ExpirePoints posts expiry debits for every user with credits that lapsed
+// by now, for the part of them that was not spent: one debit per expiry
+// time, dated at that time. It returns the number of users whose points
+// expired.
+func (s *Storage) ExpirePoints(now time.Time) (int64, error) {
+	const op = "storage.postgresql.ExpirePoints"
+
+	rows, err := s.db.Query(`
+		SELECT DISTINCT user_id FROM points_transactions
+		WHERE remaining > 0 AND expires_at <= $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("%s: select users: %w", op, err)
+	}
+	var userIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("%s: scan user: %w", op, err)
+		}
+		userIDs = append(userIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var expired int64
+	for _, userID := range userIDs {
+		amount, err := s.expireUserPoints(userID, now)
+		if err != nil {
+			return expired, fmt.Errorf("%s: user %d: %w", op, userID, err)
+		}
+		if amount > 0 {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+func (s *Storage) expireUserPoints(userID int64, now time.Time) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The user row is locked before the credits, in the same order as
+	// postEntry, so a concurrent debit cannot deadlock with the job.
+	var balance int64
+	err = tx.QueryRow(`SELECT points FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&balance)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("lock user: %w", err)
+	}
+
+	rows, err := tx.Query(`
+		WITH lapsed AS (
+			SELECT id, remaining, expires_at FROM points_transactions
+			WHERE user_id = $1 AND remaining > 0 AND expires_at <= $2
+			FOR UPDATE
+		), settled AS (
+			UPDATE points_transactions pt SET remaining = 0
+			FROM lapsed
+			WHERE pt.id = lapsed.id
+			RETURNING lapsed.remaining, lapsed.expires_at
+		)
+		SELECT expires_at, SUM(remaining)::bigint FROM settled
+		GROUP BY expires_at
+		ORDER BY expires_at`, userID, now)
+	if err != nil {
+		return 0, fmt.Errorf("settle credits: %w", err)
+	}
+	var parts []creditPart
+	for rows.Next() {
+		var part creditPart
+		if err := rows.Scan(&part.expiresAt, &part.amount); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan settled credits: %w", err)
+		}
+		parts = append(parts, part)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("settle credits: %w", err)
+	}
+
+	// Each debit is dated when its credits lapsed rather than when the job
+	// ran, so balances as of a past time do not count points that had
+	// already expired. The balance never goes negative, even if it drifted
+	// from the credits.
+	var amount int64
+	available := max(balance, 0)
+	for _, part := range parts {
+		delta := min(part.amount, available-amount)
+		if delta <= 0 {
+			break
+		}
+		_, err = postEntry(tx, models.LedgerEntry{
+			UserId:    userID,
+			Delta:     -delta,
+			Reason:    models.ReasonExpiry,
+			CreatedAt: *part.expiresAt,
+		})
+		if err != nil {
+			return 0, err
+		}
+		amount += delta
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return amount, nil
+}
+
+// GetExpiringPoints returns how many of the user's unspent points expire
+// before the given time.
+func (s *Storage) GetExpiringPoints(userID int64, before time.Time) (int64, error) {
+	const op = "storage.postgresql.GetExpiringPoints"
+
+	var amount int64
+	err := s.db.QueryRow(`
+		SELECT COALESCE(SUM(remaining), 0) FROM points_transactions
+		WHERE user_id = $1 AND remaining > 0 AND expires_at < $2`, userID, before).Scan(&amount)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return amount, nil
+}
+
+// GetHistory returns a page of the user's ledger entries, newest first, with
+// the running balance of the entry's currency after each entry. The balance
+// is computed over the whole ledger before filters are applied.
+func (s *Storage) GetHistory(userID int64, filter models.HistoryFilter) ([]models.HistoryEntry, error) {
+	const op = "storage.postgresql.GetHistory"
+
+	var exists bool
+	err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, userID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: select user: %w", op, err)
+	}
+	if !exists {
+		return nil, storage.ErrUserNotFound
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, user_id, currency, delta, reason, task_id, referral_id, counterparty_id, redemption_id, memo,
+			COALESCE(actor, ''), expires_at, base_delta, boosts, created_at, balance
+		FROM (
+			SELECT pt.*, SUM(pt.delta) OVER (PARTITION BY pt.currency ORDER BY pt.id) AS balance
+			FROM points_transactions pt
+			WHERE pt.user_id = $1
+		) h
+		WHERE ($2 = 0 OR id < $2)
+			AND ($3 = '' OR reason = $3)
+			AND ($4::timestamp IS NULL OR created_at >= $4)
+			AND ($5::timestamp IS NULL OR created_at < $5)
+			AND ($7 = '' OR currency = $7)
+		ORDER BY id DESC
+		LIMIT $6`,
+		userID, filter.Cursor, filter.Reason, filter.From, filter.To, filter.Limit, filter.Currency,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var entries []models.HistoryEntry
+	for rows.Next() {
+		var entry models.HistoryEntry
+		err := rows.Scan(
+			&entry.Id, &entry.UserId, &entry.Currency, &entry.Delta, &entry.Reason, &entry.TaskId, &entry.ReferralId,
+			&entry.CounterpartyId, &entry.RedemptionId, &entry.Memo, &entry.Actor, &entry.ExpiresAt, &entry.BaseDelta,
+			pq.Array(&entry.Boosts), &entry.CreatedAt, &entry.Balance,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return entries, nil
+}
+
+// AdjustBalance posts a manual correction by an admin. Adjustments that
+// would leave the balance negative are refused unless override is set.
+func (s *Storage) AdjustBalance(userID int64, currency string, delta int64, note string, actor string, override bool) (*models.LedgerEntry, int64, error) {
+	const op = "storage.postgresql.AdjustBalance"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var balance int64
+	err = tx.QueryRow(`SELECT points FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&balance)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, 0, storage.ErrUserNotFound
+		}
+		return nil, 0, fmt.Errorf("%s: select user: %w", op, err)
+	}
+	if currency != models.CurrencyPoints {
+		// The user row lock above also serializes adjustments of other
+		// currencies.
+		err = tx.QueryRow(`
+			SELECT COALESCE((SELECT balance FROM user_balances WHERE user_id = $1 AND currency = $2), 0)`,
+			userID, currency,
+		).Scan(&balance)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: select balance: %w", op, err)
+		}
+	}
+
+	if balance+delta < 0 && !override {
+		return nil, 0, storage.ErrInsufficientFunds
+	}
+
+	entry := models.LedgerEntry{
+		UserId:    userID,
+		Currency:  currency,
+		Delta:     delta,
+		Reason:    models.ReasonAdmin,
+		Memo:      note,
+		Actor:     actor,
+		CreatedAt: time.Now().UTC(),
+	}
+	entry.Id, err = postEntry(tx, entry)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return &entry, balance + delta, nil
+}
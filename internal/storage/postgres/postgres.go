@@ -2,17 +2,23 @@ package postgres
 
 import (
 	"database/sql"
+	"denet/internal/lib/boost"
 	"denet/internal/lib/models"
+	"denet/internal/lib/recurrence"
+	"denet/internal/lib/taskgraph"
 	"denet/internal/storage"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/lib/pq"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type Storage struct {
-	db *sql.DB
+	db     *sql.DB
+	boosts boost.Rules
 }
 
 func New(storagePath string) (*Storage, error) {
@@ -53,6 +59,30 @@ func New(storagePath string) (*Storage, error) {
 	}, nil
 }
 
+// SetBoosts sets the multiplier rules applied to task rewards. It must be
+// called before the storage is used concurrently.
+func (s *Storage) SetBoosts(rules boost.Rules) {
+	s.boosts = rules
+}
+
+// SetUserTier moves the user into tier, which selects tier-specific boosts.
+func (s *Storage) SetUserTier(userID int64, tier string) error {
+	const op = "storage.postgresql.SetUserTier"
+
+	result, err := s.db.Exec(`UPDATE users SET tier = $1 WHERE id = $2`, tier, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get affected rows count: %w", op, err)
+	}
+	if rowsAffected == 0 {
+		return storage.ErrUserNotFound
+	}
+	return nil
+}
+
 func (s *Storage) SaveUser(username, password string, points, referral_id int64) (int64, error) {
 	const op = "storage.postgresql.SaveUser"
 
@@ -61,13 +91,15 @@ func (s *Storage) SaveUser(username, password string, points, referral_id int64)
 		return 0, fmt.Errorf("%s: failed to hash password: %w", op, err)
 	}
 
-	stmt, err := s.db.Prepare("INSERT INTO users (username, password, points, referral_id) VALUES($1, $2, $3, $4) RETURNING id")
+	tx, err := s.db.Begin()
 	if err != nil {
-		return 0, fmt.Errorf("%s: %w", op, err)
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
 	}
+	defer tx.Rollback()
 
 	var id int64
-	err = stmt.QueryRow(username, string(hashedPassword), points, referral_id).Scan(&id)
+	err = tx.QueryRow("INSERT INTO users (username, password, points, referral_id) VALUES($1, $2, 0, $3) RETURNING id",
+		username, string(hashedPassword), referral_id).Scan(&id)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
 			return 0, storage.ErrUserExists
@@ -75,19 +107,34 @@ func (s *Storage) SaveUser(username, password string, points, referral_id int64)
 
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
+
+	// The starting balance goes through the ledger like any other change.
+	_, err = postEntry(tx, models.LedgerEntry{
+		UserId:    id,
+		Delta:     points,
+		Reason:    models.ReasonSignup,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: commit: %w", op, err)
+	}
 	return id, nil
 }
 
 func (s *Storage) LoginUser(username string, password string) (*models.User, error) {
 	const op = "storage.mysql.LoginUser"
-	stmt, err := s.db.Prepare("SELECT username, password FROM users WHERE username = $1")
+	stmt, err := s.db.Prepare("SELECT id, username, password, is_admin FROM users WHERE username = $1")
 	if err != nil {
 		return nil, fmt.Errorf("%s: prepare statement %w", op, err)
 	}
 
 	user := &models.User{}
 	var hashedPassword string
-	err = stmt.QueryRow(username).Scan(&user.Username, &hashedPassword)
+	err = stmt.QueryRow(username).Scan(&user.Id, &user.Username, &hashedPassword, &user.IsAdmin)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, storage.ErrUserNotFound
@@ -106,13 +153,13 @@ func (s *Storage) LoginUser(username string, password string) (*models.User, err
 func (s *Storage) GetUSER(id int64) (*models.User, error) {
 	const op = "storage.mysql.GetUSER"
 	fmt.Println(id)
-	stmt, err := s.db.Prepare("select id, username, password, points, referral_id, created_at from users where id = $1")
+	stmt, err := s.db.Prepare("select id, username, password, points, referral_id, created_at, tier, COALESCE(wallet_address, '') from users where id = $1")
 	if err != nil {
 		return nil, fmt.Errorf("%s: prepare statement %w", op, err)
 	}
 
 	user := &models.User{}
-	err = stmt.QueryRow(id).Scan(&user.Id, &user.Username, &user.Password, &user.Points, &user.Referral_id, &user.Created_at)
+	err = stmt.QueryRow(id).Scan(&user.Id, &user.Username, &user.Password, &user.Points, &user.Referral_id, &user.Created_at, &user.Tier, &user.Wallet)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, storage.ErrUserNotFound
@@ -122,19 +169,109 @@ func (s *Storage) GetUSER(id int64) (*models.User, error) {
 	return user, nil
 }
 
-func (s *Storage) GetLeaderboard() ([]models.User, error) {
+// GetTelegramID returns the Telegram account linked to the user, or 0 when
+// the user has not linked one.
+func (s *Storage) GetTelegramID(userID int64) (int64, error) {
+	const op = "storage.postgresql.GetTelegramID"
+
+	var telegramID sql.NullInt64
+	err := s.db.QueryRow(`SELECT telegram_id FROM users WHERE id = $1`, userID).Scan(&telegramID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, storage.ErrUserNotFound
+		}
+		return 0, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	return telegramID.Int64, nil
+}
+
+// LinkTelegram links the Telegram account telegramID to the user. An
+// account can only be linked to one user.
+func (s *Storage) LinkTelegram(userID int64, telegramID int64) error {
+	const op = "storage.postgresql.LinkTelegram"
+
+	return linkAccount(s.db, op, `UPDATE users SET telegram_id = $1 WHERE id = $2`, telegramID, userID)
+}
+
+// GetTwitterID returns the X account linked to the user, or an empty string
+// when the user has not linked one.
+func (s *Storage) GetTwitterID(userID int64) (string, error) {
+	const op = "storage.postgresql.GetTwitterID"
+
+	var twitterID sql.NullString
+	err := s.db.QueryRow(`SELECT twitter_id FROM users WHERE id = $1`, userID).Scan(&twitterID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", storage.ErrUserNotFound
+		}
+		return "", fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	return twitterID.String, nil
+}
+
+// LinkTwitter links the X account twitterID to the user. An account can
+// only be linked to one user.
+func (s *Storage) LinkTwitter(userID int64, twitterID string) error {
+	const op = "storage.postgresql.LinkTwitter"
+
+	return linkAccount(s.db, op, `UPDATE users SET twitter_id = $1 WHERE id = $2`, twitterID, userID)
+}
+
+// linkAccount runs query, which stores an external account ID on a user,
+// and maps its outcome to storage errors.
+func linkAccount(db *sql.DB, op string, query string, accountID any, userID int64) error {
+	result, err := db.Exec(query, accountID, userID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return storage.ErrAccountTaken
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get affected rows count: %w", op, err)
+	}
+	if rowsAffected == 0 {
+		return storage.ErrUserNotFound
+	}
+	return nil
+}
+
+// GetLeaderboard returns the top users ranked by their balance in currency.
+func (s *Storage) GetLeaderboard(currency string) ([]models.LeaderboardEntry, error) {
 	const op = "storage.mysql.GetLeaderboard"
-	query := `SELECT id, username, points, referral_id, created_at FROM users ORDER BY points DESC LIMIT 5`
-	rows, err := s.db.Query(query)
+
+	var exists bool
+	err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM currencies WHERE code = $1)`, currency).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: select currency: %w", op, err)
+	}
+	if !exists {
+		return nil, storage.ErrUnknownCurrency
+	}
+
+	query := `SELECT id, username, points, referral_id, created_at, points FROM users ORDER BY points DESC LIMIT 5`
+	args := []any{}
+	if currency != models.CurrencyPoints {
+		query = `
+			SELECT u.id, u.username, u.points, u.referral_id, u.created_at, b.balance
+			FROM user_balances b
+			JOIN users u ON u.id = b.user_id
+			WHERE b.currency = $1
+			ORDER BY b.balance DESC, u.id
+			LIMIT 5`
+		args = append(args, currency)
+	}
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var users []models.User
+	var users []models.LeaderboardEntry
 	for rows.Next() {
-		var user models.User
-		if err := rows.Scan(&user.Id, &user.Username, &user.Points, &user.Referral_id, &user.Created_at); err != nil {
+		var user models.LeaderboardEntry
+		if err := rows.Scan(&user.Id, &user.Username, &user.Points, &user.Referral_id, &user.Created_at, &user.Balance); err != nil {
 			return nil, fmt.Errorf("%s: execute statement: %w", op, err)
 		}
 		users = append(users, user)
@@ -142,32 +279,562 @@ func (s *Storage) GetLeaderboard() ([]models.User, error) {
 	return users, nil
 }
 
-func (s *Storage) CompleteTask(userID int64, taskPoints int64) error {
-	result, err := s.db.Exec(`UPDATE users SET points = points + $1 WHERE id = $2`, taskPoints, userID)
+// taskColumns lists the tasks columns read by scanTask. Queries must alias
+// the tasks table as t.
+const taskColumns = `t.id, t.slug, t.title, t.type, t.target, t.reward, t.active, t.recurrence,
+	t.cooldown_seconds, t.streak_bonus, t.starts_at, t.ends_at, COALESCE(t.max_completions, 0),
+	t.completions_count, t.counter, t.target_value, t.expiry_days,
+	COALESCE((SELECT json_object_agg(r.currency, r.amount) FROM task_rewards r WHERE r.task_id = t.id), '{}')`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTask scans taskColumns into task followed by any extra destinations.
+func scanTask(row scanner, task *models.Task, extra ...any) error {
+	var rewards []byte
+	dest := []any{
+		&task.Id, &task.Slug, &task.Title, &task.Type, &task.Target, &task.Reward, &task.Active, &task.Recurrence,
+		&task.CooldownSeconds, &task.StreakBonus, &task.StartsAt, &task.EndsAt, &task.MaxCompletions,
+		&task.CompletionsCount, &task.Counter, &task.TargetValue, &task.ExpiryDays, &rewards,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	task.Rewards = nil
+	if err := json.Unmarshal(rewards, &task.Rewards); err != nil {
+		return fmt.Errorf("decode rewards of task %d: %w", task.Id, err)
+	}
+	if len(task.Rewards) == 0 {
+		task.Rewards = nil
+	}
+	return nil
+}
+
+// setTaskRewards replaces the non-points currencies granted by a task.
+func setTaskRewards(tx *sql.Tx, taskID int64, rewards map[string]int64) error {
+	const op = "storage.postgresql.setTaskRewards"
+
+	if _, err := tx.Exec(`DELETE FROM task_rewards WHERE task_id = $1`, taskID); err != nil {
+		return fmt.Errorf("%s: delete rewards: %w", op, err)
+	}
+	for currency, amount := range rewards {
+		_, err := tx.Exec(`INSERT INTO task_rewards (task_id, currency, amount) VALUES ($1, $2, $3)`, taskID, currency, amount)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+				return fmt.Errorf("%w: %s", storage.ErrUnknownCurrency, currency)
+			}
+			return fmt.Errorf("%s: insert reward: %w", op, err)
+		}
+	}
+	return nil
+}
+
+// GetTask returns the active catalog task with the given ID.
+func (s *Storage) GetTask(id int64) (*models.Task, error) {
+	const op = "storage.postgresql.GetTask"
+
+	task := &models.Task{}
+	err := scanTask(s.db.QueryRow(`SELECT `+taskColumns+` FROM tasks t WHERE t.id = $1 AND t.active`, id), task)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	return task, nil
+}
+
+// CompleteTask credits userID with the reward of the active catalog task
+// taskID. The reward is always read from the tasks table, never supplied by
+// the caller. The completion and the user's streak are recorded in the same
+// transaction, so a task is credited at most once per recurrence period.
+func (s *Storage) CompleteTask(userID int64, taskID int64) (*models.Completion, error) {
+	const op = "storage.postgresql.CompleteTask"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	completion, err := s.completeTask(tx, userID, taskID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return completion, nil
+}
+
+func (s *Storage) completeTask(tx *sql.Tx, userID int64, taskID int64, now time.Time) (*models.Completion, error) {
+	task, err := activeTask(tx, taskID, now)
+	if err != nil {
+		return nil, err
+	}
+	if task.ProgressBased() {
+		return nil, storage.ErrTaskAutoCompleted
+	}
+	return s.creditTask(tx, userID, task, now)
+}
+
+// creditTask records a completion of task by userID and credits its reward.
+func (s *Storage) creditTask(tx *sql.Tx, userID int64, task *models.Task, now time.Time) (*models.Completion, error) {
+	const op = "storage.postgresql.creditTask"
+
+	taskID := task.Id
+	policy := task.Policy()
+
+	state, err := lockTaskState(tx, userID, task, now)
+	if err != nil {
+		return nil, err
+	}
+	if !state.exists {
+		if err := claimTaskSlot(tx, taskID); err != nil {
+			return nil, err
+		}
+	}
+
+	streak := int64(1)
+	if state.exists && policy.Consecutive(state.last, now) {
+		streak = state.streak + 1
+	}
+
+	bonus := task.StreakBonus * (streak - 1)
+	base := task.Reward + bonus
+
+	var tier string
+	if err := tx.QueryRow(`SELECT tier FROM users WHERE id = $1`, userID).Scan(&tier); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: get user tier: %w", op, err)
+	}
+	points, boosts := s.boosts.Apply(base, task.Slug, tier, now)
+
+	entry := models.LedgerEntry{
+		UserId:    userID,
+		Delta:     points,
+		Reason:    models.ReasonTask,
+		TaskId:    &taskID,
+		Boosts:    boosts,
+		CreatedAt: now,
+	}
+	if len(boosts) > 0 {
+		entry.BaseDelta = &base
+	}
+	if task.ExpiryDays > 0 {
+		expiresAt := now.AddDate(0, 0, int(task.ExpiryDays))
+		entry.ExpiresAt = &expiresAt
+	}
+	_, err = postEntry(tx, entry)
 	if err != nil {
-		return fmt.Errorf("failed to update points for user %d: %w", userID, err)
+		return nil, err
+	}
+
+	if state.exists {
+		_, err = tx.Exec(`
+			UPDATE user_task_states
+			SET last_completed_at = $3, streak = $4, completions = completions + 1
+			WHERE user_id = $1 AND task_id = $2`, userID, taskID, now, streak)
+	} else {
+		_, err = tx.Exec(`
+			INSERT INTO user_task_states (user_id, task_id, last_completed_at, streak)
+			VALUES ($1, $2, $3, $4)`, userID, taskID, now, streak)
+	}
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, storage.ErrTaskAlreadyCompleted
+		}
+		return nil, fmt.Errorf("%s: save task state: %w", op, err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO user_task_completions (user_id, task_id, points, completed_at)
+		VALUES ($1, $2, $3, $4)`, userID, taskID, points, now)
+	if err != nil {
+		return nil, fmt.Errorf("%s: insert completion: %w", op, err)
+	}
+
+	if task.Recurrence == recurrence.Daily {
+		if err := s.incrementProgress(tx, userID, models.CounterCheckIns, 1, now); err != nil {
+			return nil, err
+		}
+	}
+
+	for currency, amount := range task.Rewards {
+		_, err = postEntry(tx, models.LedgerEntry{
+			UserId:    userID,
+			Currency:  currency,
+			Delta:     amount,
+			Reason:    models.ReasonTask,
+			TaskId:    &taskID,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	completion := &models.Completion{
+		Points:  points,
+		Base:    base,
+		Bonus:   bonus,
+		Streak:  streak,
+		Rewards: task.Rewards,
+		Boosts:  boosts,
+	}
+	if policy.Repeatable() {
+		completion.NextAvailableAt, _ = policy.NextAvailable(now)
+	} else {
+		completion.Streak = 0
+	}
+	return completion, nil
+}
+
+// activeTask returns the active catalog task taskID, failing when it is
+// outside of its availability window at now.
+func activeTask(tx *sql.Tx, taskID int64, now time.Time) (*models.Task, error) {
+	const op = "storage.postgresql.activeTask"
+
+	task := &models.Task{}
+	err := scanTask(tx.QueryRow(`SELECT `+taskColumns+` FROM tasks t WHERE t.id = $1 AND t.active`, taskID), task)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if state, ok := task.Window(now); !ok {
+		if state == models.TaskUpcoming {
+			return nil, storage.ErrTaskNotStarted
+		}
+		return nil, storage.ErrTaskExpired
+	}
+	return task, nil
+}
+
+// claimTaskSlot counts a new participant of taskID. The conditional update
+// takes the row lock, so concurrent claims cannot exceed max_completions.
+func claimTaskSlot(tx *sql.Tx, taskID int64) error {
+	const op = "storage.postgresql.claimTaskSlot"
+
+	result, err := tx.Exec(`
+		UPDATE tasks SET completions_count = completions_count + 1
+		WHERE id = $1 AND (max_completions IS NULL OR completions_count < max_completions)`, taskID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to get affected rows count: %w", err)
+		return fmt.Errorf("%s: get affected rows count: %w", op, err)
 	}
 	if rowsAffected == 0 {
-		return storage.ErrUserNotFound
+		return storage.ErrTaskExhausted
+	}
+	return nil
+}
+
+type taskState struct {
+	exists bool
+	last   time.Time
+	streak int64
+}
+
+// lockTaskState locks and returns the user's progress on task, failing when
+// the task cannot be completed by userID at now.
+func lockTaskState(tx *sql.Tx, userID int64, task *models.Task, now time.Time) (*taskState, error) {
+	const op = "storage.postgresql.lockTaskState"
+
+	missing, err := missingPrerequisites(tx, userID, task.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(missing) > 0 {
+		return nil, &storage.PrerequisitesError{Missing: missing}
+	}
+
+	state := &taskState{}
+	err = tx.QueryRow(`
+		SELECT last_completed_at, streak FROM user_task_states
+		WHERE user_id = $1 AND task_id = $2
+		FOR UPDATE`, userID, task.Id).Scan(&state.last, &state.streak)
+	if errors.Is(err, sql.ErrNoRows) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: select task state: %w", op, err)
+	}
+	state.exists = true
+
+	next, ok := task.Policy().NextAvailable(state.last)
+	if !ok {
+		return nil, storage.ErrTaskAlreadyCompleted
+	}
+	if now.Before(next) {
+		return nil, &storage.CooldownError{Until: next}
+	}
+	return state, nil
+}
+
+// missingPrerequisites returns the prerequisites of taskID that userID has not
+// completed yet.
+func missingPrerequisites(tx *sql.Tx, userID int64, taskID int64) ([]models.Task, error) {
+	rows, err := tx.Query(`
+		SELECT t.id, t.slug, t.title
+		FROM task_prerequisites p
+		JOIN tasks t ON t.id = p.prerequisite_id
+		WHERE p.task_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM user_task_states s
+			WHERE s.user_id = $2 AND s.task_id = p.prerequisite_id
+		)
+		ORDER BY t.id`, taskID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("select missing prerequisites: %w", err)
+	}
+	defer rows.Close()
+
+	var missing []models.Task
+	for rows.Next() {
+		var task models.Task
+		if err := rows.Scan(&task.Id, &task.Slug, &task.Title); err != nil {
+			return nil, fmt.Errorf("scan prerequisite: %w", err)
+		}
+		missing = append(missing, task)
+	}
+	return missing, rows.Err()
+}
+
+// CreateTask adds a task to the catalog together with its prerequisites and
+// returns the new task ID.
+func (s *Storage) CreateTask(task models.Task, prerequisites []int64) (int64, error) {
+	const op = "storage.postgresql.CreateTask"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRow(`
+		INSERT INTO tasks (slug, title, type, target, reward, active, recurrence, cooldown_seconds, streak_bonus,
+			starts_at, ends_at, max_completions, counter, target_value, expiry_days)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, 0), $13, $14, $15)
+		RETURNING id`,
+		task.Slug, task.Title, task.Type, task.Target, task.Reward, task.Active, task.Recurrence, task.CooldownSeconds, task.StreakBonus,
+		task.StartsAt, task.EndsAt, task.MaxCompletions, task.Counter, task.TargetValue, task.ExpiryDays,
+	).Scan(&id)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return 0, storage.ErrTaskExists
+		}
+		return 0, fmt.Errorf("%s: insert task: %w", op, err)
+	}
+
+	if err := setTaskRewards(tx, id, task.Rewards); err != nil {
+		return 0, err
+	}
+	if err := setPrerequisites(tx, map[int64][]int64{id: prerequisites}); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return id, nil
+}
+
+// SetTaskPrerequisites replaces the prerequisites of taskID, refusing changes
+// that would make the prerequisite graph cyclic.
+func (s *Storage) SetTaskPrerequisites(taskID int64, prerequisites []int64) error {
+	const op = "storage.postgresql.SetTaskPrerequisites"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1)`, taskID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("%s: select task: %w", op, err)
+	}
+	if !exists {
+		return storage.ErrTaskNotFound
+	}
+
+	if err := setPrerequisites(tx, map[int64][]int64{taskID: prerequisites}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return nil
+}
+
+// setPrerequisites replaces the prerequisite edges of every task in
+// prerequisites and checks that the resulting graph is still acyclic. All
+// edges are replaced before the check, so edges may be reversed in one call.
+// The edge table is locked for the rest of the transaction so concurrent edits
+// cannot introduce a cycle together.
+func setPrerequisites(tx *sql.Tx, prerequisites map[int64][]int64) error {
+	const op = "storage.postgresql.setPrerequisites"
+
+	if _, err := tx.Exec(`LOCK TABLE task_prerequisites IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("%s: lock prerequisites: %w", op, err)
+	}
+
+	for taskID := range prerequisites {
+		if _, err := tx.Exec(`DELETE FROM task_prerequisites WHERE task_id = $1`, taskID); err != nil {
+			return fmt.Errorf("%s: delete prerequisites: %w", op, err)
+		}
+	}
+	for taskID, prerequisiteIDs := range prerequisites {
+		for _, prerequisiteID := range prerequisiteIDs {
+			if prerequisiteID == taskID {
+				return fmt.Errorf("%w: task %d depends on itself", storage.ErrPrerequisiteCycle, taskID)
+			}
+			_, err := tx.Exec(`
+				INSERT INTO task_prerequisites (task_id, prerequisite_id) VALUES ($1, $2)
+				ON CONFLICT DO NOTHING`, taskID, prerequisiteID)
+			if err != nil {
+				if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+					return fmt.Errorf("%w: prerequisite %d", storage.ErrTaskNotFound, prerequisiteID)
+				}
+				return fmt.Errorf("%s: insert prerequisite: %w", op, err)
+			}
+		}
+	}
+
+	rows, err := tx.Query(`SELECT task_id, prerequisite_id FROM task_prerequisites`)
+	if err != nil {
+		return fmt.Errorf("%s: select prerequisites: %w", op, err)
+	}
+	defer rows.Close()
+
+	edges := make(map[int64][]int64)
+	for rows.Next() {
+		var from, to int64
+		if err := rows.Scan(&from, &to); err != nil {
+			return fmt.Errorf("%s: scan prerequisite: %w", op, err)
+		}
+		edges[from] = append(edges[from], to)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s: select prerequisites: %w", op, err)
+	}
+
+	if cycle := taskgraph.FindCycle(edges); cycle != nil {
+		return fmt.Errorf("%w: %v", storage.ErrPrerequisiteCycle, cycle)
 	}
 	return nil
 }
 
+// GetUserStreak returns the longest streak among the user's recurring tasks
+// that can still be continued.
+func (s *Storage) GetUserStreak(userID int64) (int64, error) {
+	const op = "storage.postgresql.GetUserStreak"
+
+	rows, err := s.db.Query(`
+		SELECT t.recurrence, t.cooldown_seconds, s.last_completed_at, s.streak
+		FROM user_task_states s
+		JOIN tasks t ON t.id = s.task_id
+		WHERE s.user_id = $1 AND t.recurrence <> 'once'`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	now := time.Now().UTC()
+	var best int64
+	for rows.Next() {
+		var task models.Task
+		var last time.Time
+		var streak int64
+		if err := rows.Scan(&task.Recurrence, &task.CooldownSeconds, &last, &streak); err != nil {
+			return 0, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		if task.Policy().StreakAlive(last, now) && streak > best {
+			best = streak
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return best, nil
+}
+
+// referralBonus is credited to a user for entering a referral code.
+const referralBonus = 5
+
+// SetReferral stores the user who referred userID, credits userID with the
+// referral bonus and advances the referrer's referral counter. The referrer
+// can only be set once, so switching referrers cannot earn the bonus again
+// or inflate anyone's referral count.
 func (s *Storage) SetReferral(userID int64, referralID int64) error {
-	result, err := s.db.Exec(`UPDATE users SET referral_id = $1, points=points+5 WHERE id = $2`, referralID, userID)
+	const op = "storage.postgresql.SetReferral"
+
+	if userID == referralID {
+		return storage.ErrSelfReferral
+	}
+
+	tx, err := s.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to update points for user %d: %w", userID, err)
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
 	}
-	rowsAffected, err := result.RowsAffected()
+	defer tx.Rollback()
+
+	var previousID int64
+	err = tx.QueryRow(`SELECT referral_id FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&previousID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.ErrUserNotFound
+		}
+		return fmt.Errorf("%s: select user: %w", op, err)
+	}
+	if previousID != 0 {
+		return storage.ErrReferralSet
+	}
+
+	var referrerExists bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, referralID).Scan(&referrerExists)
 	if err != nil {
-		return fmt.Errorf("failed to get affected rows count: %w", err)
+		return fmt.Errorf("%s: select referrer: %w", op, err)
 	}
-	if rowsAffected == 0 {
-		return storage.ErrUserNotFound
+	if !referrerExists {
+		return storage.ErrReferrerNotFound
+	}
+
+	_, err = tx.Exec(`UPDATE users SET referral_id = $1 WHERE id = $2`, referralID, userID)
+	if err != nil {
+		return fmt.Errorf("%s: update referral for user %d: %w", op, userID, err)
+	}
+
+	now := time.Now().UTC()
+	_, err = postEntry(tx, models.LedgerEntry{
+		UserId:     userID,
+		Delta:      referralBonus,
+		Reason:     models.ReasonReferral,
+		ReferralId: &referralID,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.incrementProgress(tx, referralID, models.CounterReferrals, 1, now); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
 	}
 	return nil
 }
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"database/sql"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// incrementProgress advances counter by delta on every open progress-based
+// task tracking it and credits, exactly once, the tasks whose target is
+// reached. Tasks that cannot be credited yet, e.g. because of missing
+// prerequisites, keep their progress and are retried on the next increment.
+func (s *Storage) incrementProgress(tx *sql.Tx, userID int64, counter string, delta int64, now time.Time) error {
+	const op = "storage.postgresql.incrementProgress"
+
+	rows, err := tx.Query(`
+		SELECT t.id FROM tasks t
+		WHERE t.active AND t.counter = $1 AND t.target_value > 0
+			AND (t.starts_at IS NULL OR t.starts_at <= $2)
+			AND (t.ends_at IS NULL OR t.ends_at > $2)
+		ORDER BY t.id`, counter, now)
+	if err != nil {
+		return fmt.Errorf("%s: select tasks: %w", op, err)
+	}
+	var taskIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("%s: scan task: %w", op, err)
+		}
+		taskIDs = append(taskIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, taskID := range taskIDs {
+		var current int64
+		err := tx.QueryRow(`
+			INSERT INTO user_task_progress (user_id, task_id, current) VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, task_id) DO UPDATE
+			SET current = user_task_progress.current + EXCLUDED.current
+			WHERE user_task_progress.completed_at IS NULL
+			RETURNING current`, userID, taskID, delta).Scan(&current)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue // already completed
+		}
+		if err != nil {
+			return fmt.Errorf("%s: update progress: %w", op, err)
+		}
+
+		task, err := activeTask(tx, taskID, now)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if current < task.TargetValue {
+			continue
+		}
+
+		credited, err := s.creditProgressTask(tx, userID, task, now)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if !credited {
+			continue
+		}
+
+		_, err = tx.Exec(`
+			UPDATE user_task_progress SET completed_at = $3
+			WHERE user_id = $1 AND task_id = $2`, userID, taskID, now)
+		if err != nil {
+			return fmt.Errorf("%s: complete progress: %w", op, err)
+		}
+	}
+	return nil
+}
+
+// creditProgressTask credits a progress-based task inside a savepoint, so a
+// refused completion leaves the surrounding transaction usable. It reports
+// whether the reward was credited.
+func (s *Storage) creditProgressTask(tx *sql.Tx, userID int64, task *models.Task, now time.Time) (bool, error) {
+	if _, err := tx.Exec(`SAVEPOINT credit_progress`); err != nil {
+		return false, fmt.Errorf("savepoint: %w", err)
+	}
+
+	_, err := s.creditTask(tx, userID, task, now)
+	if err == nil {
+		_, err = tx.Exec(`RELEASE SAVEPOINT credit_progress`)
+		return err == nil, err
+	}
+
+	if _, rbErr := tx.Exec(`ROLLBACK TO SAVEPOINT credit_progress`); rbErr != nil {
+		return false, fmt.Errorf("rollback to savepoint: %w", rbErr)
+	}
+	if errors.Is(err, storage.ErrTaskAlreadyCompleted) ||
+		errors.Is(err, storage.ErrTaskExhausted) ||
+		errors.Is(err, storage.ErrPrerequisitesMissing) {
+		return false, nil
+	}
+	return false, err
+}
+
+// GetUserProgress returns the user's counters on progress-based tasks.
+func (s *Storage) GetUserProgress(userID int64) ([]models.Progress, error) {
+	const op = "storage.postgresql.GetUserProgress"
+
+	rows, err := s.db.Query(`
+		SELECT t.id, t.slug, COALESCE(p.current, 0), t.target_value, p.completed_at
+		FROM tasks t
+		LEFT JOIN user_task_progress p ON p.task_id = t.id AND p.user_id = $1
+		WHERE t.target_value > 0 AND (t.active OR p.user_id IS NOT NULL)
+		ORDER BY t.id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var progress []models.Progress
+	for rows.Next() {
+		var p models.Progress
+		if err := rows.Scan(&p.TaskId, &p.TaskSlug, &p.Current, &p.Target, &p.CompletedAt); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		progress = append(progress, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return progress, nil
+}
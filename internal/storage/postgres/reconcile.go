@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"database/sql"
+	"denet/internal/lib/models"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ReconcileBalances compares every user's points column with the sum of
+// their points ledger. With repair set, each mismatch is closed by a
+// correction entry. The balance itself is left as is: it is what the user
+// has seen, so the ledger is made to explain it.
+func (s *Storage) ReconcileBalances(repair bool) (*models.ReconcileReport, error) {
+	const op = "storage.postgresql.ReconcileBalances"
+
+	report := &models.ReconcileReport{
+		CheckedAt:  time.Now().UTC(),
+		Mismatches: []models.Mismatch{},
+	}
+
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&report.Users)
+	if err != nil {
+		return nil, fmt.Errorf("%s: count users: %w", op, err)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT u.id, u.username, COALESCE(u.points, 0), COALESCE(l.total, 0)
+		FROM users u
+		LEFT JOIN (
+			SELECT user_id, SUM(delta) AS total
+			FROM points_transactions
+			WHERE currency = $1
+			GROUP BY user_id
+		) l ON l.user_id = u.id
+		WHERE COALESCE(u.points, 0) <> COALESCE(l.total, 0)
+		ORDER BY u.id`, models.CurrencyPoints)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for rows.Next() {
+		var m models.Mismatch
+		if err := rows.Scan(&m.UserId, &m.Username, &m.Balance, &m.LedgerSum); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		m.Difference = m.Balance - m.LedgerSum
+		report.Mismatches = append(report.Mismatches, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !repair {
+		return report, nil
+	}
+	for i := range report.Mismatches {
+		if err := s.repairBalance(&report.Mismatches[i], report.CheckedAt); err != nil {
+			return report, fmt.Errorf("%s: user %d: %w", op, report.Mismatches[i].UserId, err)
+		}
+	}
+	return report, nil
+}
+
+// repairBalance posts a correction entry for one mismatch. The difference is
+// computed again under the user lock, since it may have changed since the
+// scan.
+func (s *Storage) repairBalance(m *models.Mismatch, now time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		SELECT COALESCE(points, 0),
+			(SELECT COALESCE(SUM(delta), 0) FROM points_transactions WHERE user_id = $1 AND currency = $2)
+		FROM users
+		WHERE id = $1
+		FOR UPDATE`, m.UserId, models.CurrencyPoints).Scan(&m.Balance, &m.LedgerSum)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("lock user: %w", err)
+	}
+	m.Difference = m.Balance - m.LedgerSum
+	if m.Difference == 0 {
+		m.Repaired = true
+		return nil
+	}
+
+	// The correction is recorded without going through postEntry, because
+	// the balance already includes it.
+	if m.Difference < 0 {
+		if err := spendCredits(tx, m.UserId, -m.Difference); err != nil {
+			return err
+		}
+	}
+	err = tx.QueryRow(`
+		INSERT INTO points_transactions (user_id, currency, delta, reason, memo, remaining, created_at)
+		VALUES ($1, $2, $3, $4, $5, GREATEST($3, 0), $6)
+		RETURNING id`,
+		m.UserId, models.CurrencyPoints, m.Difference, models.ReasonCorrection, "reconciliation", now,
+	).Scan(&m.EntryId)
+	if err != nil {
+		return fmt.Errorf("insert correction: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	m.Repaired = true
+	return nil
+}
@@ -0,0 +1,371 @@
+package postgres
+
+import (
+	"database/sql"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ListRewards returns the rewards shop, cheapest first. Inactive rewards are
+// only listed when all is set.
+func (s *Storage) ListRewards(all bool) ([]models.Reward, error) {
+	const op = "storage.postgresql.ListRewards"
+
+	rows, err := s.db.Query(`
+		SELECT id, slug, title, description, price, stock, active, code_pool, created_at
+		FROM rewards
+		WHERE $1 OR active
+		ORDER BY price, id`, all)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var rewards []models.Reward
+	for rows.Next() {
+		var reward models.Reward
+		err := rows.Scan(
+			&reward.Id, &reward.Slug, &reward.Title, &reward.Description, &reward.Price, &reward.Stock,
+			&reward.Active, &reward.CodePool, &reward.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		rewards = append(rewards, reward)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return rewards, nil
+}
+
+func (s *Storage) CreateReward(reward models.Reward) (int64, error) {
+	const op = "storage.postgresql.CreateReward"
+
+	var id int64
+	err := s.db.QueryRow(`
+		INSERT INTO rewards (slug, title, description, price, stock, active, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		reward.Slug, reward.Title, reward.Description, reward.Price, reward.Stock, reward.Active, time.Now().UTC(),
+	).Scan(&id)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return 0, storage.ErrRewardExists
+		}
+		return 0, fmt.Errorf("%s: insert reward: %w", op, err)
+	}
+	return id, nil
+}
+
+// RedeemReward buys one unit of the reward for the user. The price is
+// debited and the stock decremented in the same transaction, and a pending
+// redemption is recorded for an admin to fulfil. Rewards with a code pool get
+// an unused voucher code instead and are fulfilled immediately.
+func (s *Storage) RedeemReward(userID int64, rewardID int64) (*models.Redemption, error) {
+	const op = "storage.postgresql.RedeemReward"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var balance int64
+	err = tx.QueryRow(`SELECT points FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&balance)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: select user: %w", op, err)
+	}
+
+	redemption := models.Redemption{
+		UserId:    userID,
+		RewardId:  rewardID,
+		Status:    models.RedemptionPending,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	// The conditional update claims a unit of stock atomically, so concurrent
+	// redemptions cannot oversell.
+	var codePool bool
+	err = tx.QueryRow(`
+		UPDATE rewards SET stock = stock - 1
+		WHERE id = $1 AND active AND stock > 0
+		RETURNING slug, title, price, code_pool`, rewardID,
+	).Scan(&redemption.RewardSlug, &redemption.RewardTitle, &redemption.Price, &codePool)
+	if errors.Is(err, sql.ErrNoRows) {
+		var active bool
+		err = tx.QueryRow(`SELECT active FROM rewards WHERE id = $1`, rewardID).Scan(&active)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && !active) {
+			return nil, storage.ErrRewardNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: select reward: %w", op, err)
+		}
+		return nil, storage.ErrOutOfStock
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: claim stock: %w", op, err)
+	}
+
+	if balance < redemption.Price {
+		return nil, storage.ErrInsufficientFunds
+	}
+
+	// A voucher code is delivered right away, so there is nothing left for
+	// an admin to fulfil.
+	if codePool {
+		redemption.Status = models.RedemptionFulfilled
+		redemption.UpdatedAt = &redemption.CreatedAt
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO redemptions (user_id, reward_id, price, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		userID, rewardID, redemption.Price, redemption.Status, redemption.CreatedAt, redemption.UpdatedAt,
+	).Scan(&redemption.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: insert redemption: %w", op, err)
+	}
+
+	if codePool {
+		redemption.Code, err = assignCode(tx, rewardID, redemption.Id, redemption.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = postEntry(tx, models.LedgerEntry{
+		UserId:       userID,
+		Delta:        -redemption.Price,
+		Reason:       models.ReasonRedemption,
+		RedemptionId: &redemption.Id,
+		CreatedAt:    redemption.CreatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return &redemption, nil
+}
+
+// ListRedemptions returns redemptions with the given status, oldest first.
+// An empty status lists every redemption.
+func (s *Storage) ListRedemptions(status string) ([]models.Redemption, error) {
+	const op = "storage.postgresql.ListRedemptions"
+
+	rows, err := s.db.Query(`
+		SELECT d.id, d.user_id, u.username, d.reward_id, r.slug, r.title, d.price, COALESCE(c.code, ''), d.status,
+			d.note, COALESCE(d.updated_by, ''), d.created_at, d.updated_at
+		FROM redemptions d
+		JOIN users u ON u.id = d.user_id
+		JOIN rewards r ON r.id = d.reward_id
+		LEFT JOIN reward_codes c ON c.redemption_id = d.id
+		WHERE $1 = '' OR d.status = $1
+		ORDER BY d.created_at, d.id`, status)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	redemptions, err := scanRedemptions(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return redemptions, nil
+}
+
+// GetUserRedemptions returns the user's redemptions, newest first.
+func (s *Storage) GetUserRedemptions(userID int64) ([]models.Redemption, error) {
+	const op = "storage.postgresql.GetUserRedemptions"
+
+	rows, err := s.db.Query(`
+		SELECT d.id, d.user_id, '', d.reward_id, r.slug, r.title, d.price, COALESCE(c.code, ''), d.status,
+			d.note, COALESCE(d.updated_by, ''), d.created_at, d.updated_at
+		FROM redemptions d
+		JOIN rewards r ON r.id = d.reward_id
+		LEFT JOIN reward_codes c ON c.redemption_id = d.id
+		WHERE d.user_id = $1
+		ORDER BY d.created_at DESC, d.id DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	redemptions, err := scanRedemptions(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return redemptions, nil
+}
+
+func scanRedemptions(rows *sql.Rows) ([]models.Redemption, error) {
+	var redemptions []models.Redemption
+	for rows.Next() {
+		var redemption models.Redemption
+		var updatedAt sql.NullTime
+		err := rows.Scan(
+			&redemption.Id, &redemption.UserId, &redemption.Username, &redemption.RewardId, &redemption.RewardSlug,
+			&redemption.RewardTitle, &redemption.Price, &redemption.Code, &redemption.Status, &redemption.Note,
+			&redemption.UpdatedBy, &redemption.CreatedAt, &updatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan redemption: %w", err)
+		}
+		if updatedAt.Valid {
+			redemption.UpdatedAt = &updatedAt.Time
+		}
+		redemptions = append(redemptions, redemption)
+	}
+	return redemptions, rows.Err()
+}
+
+// UpdateRedemptionStatus moves a pending redemption to fulfilled or
+// cancelled. Cancelling refunds the price and returns the unit to stock.
+func (s *Storage) UpdateRedemptionStatus(id int64, status string, admin string, note string) error {
+	const op = "storage.postgresql.UpdateRedemptionStatus"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var userID, rewardID, price int64
+	var current string
+	err = tx.QueryRow(`
+		SELECT user_id, reward_id, price, status FROM redemptions
+		WHERE id = $1
+		FOR UPDATE`, id).Scan(&userID, &rewardID, &price, &current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.ErrRedemptionNotFound
+		}
+		return fmt.Errorf("%s: select redemption: %w", op, err)
+	}
+	if current != models.RedemptionPending {
+		return storage.ErrRedemptionFinalized
+	}
+
+	now := time.Now().UTC()
+	if status == models.RedemptionCancelled {
+		_, err = tx.Exec(`UPDATE rewards SET stock = stock + 1 WHERE id = $1`, rewardID)
+		if err != nil {
+			return fmt.Errorf("%s: restock reward: %w", op, err)
+		}
+		_, err = postEntry(tx, models.LedgerEntry{
+			UserId:       userID,
+			Delta:        price,
+			Reason:       models.ReasonRedemption,
+			RedemptionId: &id,
+			Memo:         "refund",
+			CreatedAt:    now,
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE redemptions
+		SET status = $2, note = $3, updated_by = $4, updated_at = $5
+		WHERE id = $1`, id, status, note, admin, now)
+	if err != nil {
+		return fmt.Errorf("%s: update redemption: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return nil
+}
+
+// assignCode hands the oldest unused code of the reward to the redemption.
+// SKIP LOCKED lets concurrent redemptions take different codes instead of
+// waiting on, and then sharing, the same row.
+func assignCode(tx *sql.Tx, rewardID int64, redemptionID int64, now time.Time) (string, error) {
+	const op = "storage.postgresql.assignCode"
+
+	var id int64
+	var code string
+	err := tx.QueryRow(`
+		SELECT id, code FROM reward_codes
+		WHERE reward_id = $1 AND assigned_at IS NULL
+		ORDER BY id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`, rewardID).Scan(&id, &code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", storage.ErrOutOfStock
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: select code: %w", op, err)
+	}
+
+	_, err = tx.Exec(`UPDATE reward_codes SET redemption_id = $2, assigned_at = $3 WHERE id = $1`, id, redemptionID, now)
+	if err != nil {
+		return "", fmt.Errorf("%s: assign code: %w", op, err)
+	}
+	return code, nil
+}
+
+// AddRewardCodes uploads voucher codes for a reward and turns it into a code
+// pool reward. Codes already in the pool are skipped, and the stock is reset
+// to the number of unused codes.
+func (s *Storage) AddRewardCodes(rewardID int64, codes []string) (int64, error) {
+	const op = "storage.postgresql.AddRewardCodes"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow(`SELECT TRUE FROM rewards WHERE id = $1 FOR UPDATE`, rewardID).Scan(&exists)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, storage.ErrRewardNotFound
+		}
+		return 0, fmt.Errorf("%s: select reward: %w", op, err)
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO reward_codes (reward_id, code, created_at)
+		SELECT $1, code, $3 FROM unnest($2::text[]) AS code
+		ON CONFLICT (reward_id, code) DO NOTHING`,
+		rewardID, pq.Array(codes), time.Now().UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: insert codes: %w", op, err)
+	}
+	added, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: get affected rows count: %w", op, err)
+	}
+
+	// Stock of a code pool reward always matches its unused codes.
+	_, err = tx.Exec(`
+		UPDATE rewards SET code_pool = TRUE, stock = (
+			SELECT COUNT(*) FROM reward_codes WHERE reward_id = $1 AND assigned_at IS NULL
+		)
+		WHERE id = $1`, rewardID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: update stock: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return added, nil
+}
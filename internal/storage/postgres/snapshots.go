@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"database/sql"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GetBalanceAt returns the user's balance in currency as of at, summed from
+// the ledger. Entries created exactly at at are included.
+func (s *Storage) GetBalanceAt(userID int64, currency string, at time.Time) (int64, error) {
+	const op = "storage.postgresql.GetBalanceAt"
+
+	var userExists, currencyExists bool
+	err := s.db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM users WHERE id = $1), EXISTS (SELECT 1 FROM currencies WHERE code = $2)`,
+		userID, currency,
+	).Scan(&userExists, &currencyExists)
+	if err != nil {
+		return 0, fmt.Errorf("%s: check user: %w", op, err)
+	}
+	if !userExists {
+		return 0, storage.ErrUserNotFound
+	}
+	if !currencyExists {
+		return 0, storage.ErrUnknownCurrency
+	}
+
+	var balance int64
+	err = s.db.QueryRow(`
+		SELECT COALESCE(SUM(delta), 0)
+		FROM points_transactions
+		WHERE user_id = $1 AND currency = $2 AND created_at <= $3`,
+		userID, currency, at.UTC(),
+	).Scan(&balance)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return balance, nil
+}
+
+// CreateSnapshot materialises the positive balances in currency as of at,
+// ranked from the highest. Users with equal balances share a rank.
+func (s *Storage) CreateSnapshot(currency string, at time.Time, admin string) (*models.Snapshot, error) {
+	const op = "storage.postgresql.CreateSnapshot"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM currencies WHERE code = $1)`, currency).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: select currency: %w", op, err)
+	}
+	if !exists {
+		return nil, storage.ErrUnknownCurrency
+	}
+
+	snapshot := &models.Snapshot{
+		Currency:  currency,
+		TakenAt:   at.UTC(),
+		CreatedBy: admin,
+		CreatedAt: time.Now().UTC(),
+	}
+	err = tx.QueryRow(`
+		INSERT INTO balance_snapshots (currency, taken_at, created_by, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`,
+		snapshot.Currency, snapshot.TakenAt, snapshot.CreatedBy, snapshot.CreatedAt,
+	).Scan(&snapshot.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: insert snapshot: %w", op, err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO snapshot_entries (snapshot_id, user_id, rank, balance)
+		SELECT $1, user_id, RANK() OVER (ORDER BY SUM(delta) DESC), SUM(delta)
+		FROM points_transactions
+		WHERE currency = $2 AND created_at <= $3
+		GROUP BY user_id
+		HAVING SUM(delta) > 0`,
+		snapshot.Id, snapshot.Currency, snapshot.TakenAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: insert entries: %w", op, err)
+	}
+
+	err = tx.QueryRow(`
+		UPDATE balance_snapshots s
+		SET users = e.users, total = e.total
+		FROM (
+			SELECT COUNT(*) AS users, COALESCE(SUM(balance), 0) AS total
+			FROM snapshot_entries
+			WHERE snapshot_id = $1
+		) e
+		WHERE s.id = $1
+		RETURNING s.users, s.total`, snapshot.Id,
+	).Scan(&snapshot.Users, &snapshot.Total)
+	if err != nil {
+		return nil, fmt.Errorf("%s: update totals: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return snapshot, nil
+}
+
+// GetSnapshot returns a snapshot with its entries ordered by rank.
+func (s *Storage) GetSnapshot(id int64) (*models.Snapshot, error) {
+	const op = "storage.postgresql.GetSnapshot"
+
+	snapshot := &models.Snapshot{}
+	err := s.db.QueryRow(`
+		SELECT id, currency, taken_at, users, total, created_by, created_at
+		FROM balance_snapshots
+		WHERE id = $1`, id,
+	).Scan(&snapshot.Id, &snapshot.Currency, &snapshot.TakenAt, &snapshot.Users, &snapshot.Total,
+		&snapshot.CreatedBy, &snapshot.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrSnapshotNotFound
+		}
+		return nil, fmt.Errorf("%s: select snapshot: %w", op, err)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT e.rank, e.user_id, u.username, e.balance
+		FROM snapshot_entries e
+		JOIN users u ON u.id = e.user_id
+		WHERE e.snapshot_id = $1
+		ORDER BY e.rank, e.user_id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: select entries: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.SnapshotEntry
+		if err := rows.Scan(&entry.Rank, &entry.UserId, &entry.Username, &entry.Balance); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		snapshot.Entries = append(snapshot.Entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return snapshot, nil
+}
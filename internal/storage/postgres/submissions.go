@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"database/sql"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// SubmitTask stores a pending proof for a manually moderated task. Nothing is
+// credited until a moderator approves the submission.
+func (s *Storage) SubmitTask(userID int64, taskID int64, proofURL string, proofText string) (int64, error) {
+	const op = "storage.postgresql.SubmitTask"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	task, err := activeTask(tx, taskID, now)
+	if err != nil {
+		return 0, err
+	}
+	if task.ProgressBased() {
+		return 0, storage.ErrTaskAutoCompleted
+	}
+	if _, err := lockTaskState(tx, userID, task, now); err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err = tx.QueryRow(`
+		INSERT INTO task_submissions (user_id, task_id, proof_url, proof_text, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`, userID, taskID, proofURL, proofText, now).Scan(&id)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case "23505":
+				return 0, storage.ErrSubmissionPending
+			case "23503":
+				return 0, storage.ErrUserNotFound
+			}
+		}
+		return 0, fmt.Errorf("%s: insert submission: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return id, nil
+}
+
+// ListSubmissions returns submissions with the given status, oldest first.
+// An empty status lists every submission.
+func (s *Storage) ListSubmissions(status string) ([]models.Submission, error) {
+	const op = "storage.postgresql.ListSubmissions"
+
+	rows, err := s.db.Query(`
+		SELECT s.id, s.user_id, u.username, s.task_id, t.slug, s.proof_url, s.proof_text,
+			s.status, s.reason, COALESCE(s.reviewed_by, ''), s.created_at, s.reviewed_at
+		FROM task_submissions s
+		JOIN users u ON u.id = s.user_id
+		JOIN tasks t ON t.id = s.task_id
+		WHERE $1 = '' OR s.status = $1
+		ORDER BY s.created_at, s.id`, status)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	submissions, err := scanSubmissions(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return submissions, nil
+}
+
+// GetUserSubmissions returns the user's submissions, newest first.
+func (s *Storage) GetUserSubmissions(userID int64) ([]models.Submission, error) {
+	const op = "storage.postgresql.GetUserSubmissions"
+
+	rows, err := s.db.Query(`
+		SELECT s.id, s.user_id, '', s.task_id, t.slug, s.proof_url, s.proof_text,
+			s.status, s.reason, COALESCE(s.reviewed_by, ''), s.created_at, s.reviewed_at
+		FROM task_submissions s
+		JOIN tasks t ON t.id = s.task_id
+		WHERE s.user_id = $1
+		ORDER BY s.created_at DESC, s.id DESC
+		LIMIT 20`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	submissions, err := scanSubmissions(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return submissions, nil
+}
+
+func scanSubmissions(rows *sql.Rows) ([]models.Submission, error) {
+	var submissions []models.Submission
+	for rows.Next() {
+		var sub models.Submission
+		var reviewedAt sql.NullTime
+		err := rows.Scan(
+			&sub.Id, &sub.UserId, &sub.Username, &sub.TaskId, &sub.TaskSlug, &sub.ProofURL, &sub.ProofText,
+			&sub.Status, &sub.Reason, &sub.ReviewedBy, &sub.CreatedAt, &reviewedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan submission: %w", err)
+		}
+		if reviewedAt.Valid {
+			sub.ReviewedAt = &reviewedAt.Time
+		}
+		submissions = append(submissions, sub)
+	}
+	return submissions, rows.Err()
+}
+
+// ApproveSubmission marks a pending submission as approved and credits the
+// task reward in the same transaction.
+func (s *Storage) ApproveSubmission(id int64, reviewer string) (*models.Completion, error) {
+	const op = "storage.postgresql.ApproveSubmission"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	userID, taskID, err := lockPendingSubmission(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	completion, err := s.completeTask(tx, userID, taskID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE task_submissions
+		SET status = $2, reviewed_by = $3, reviewed_at = $4
+		WHERE id = $1`, id, models.SubmissionApproved, reviewer, now)
+	if err != nil {
+		return nil, fmt.Errorf("%s: update submission: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return completion, nil
+}
+
+// RejectSubmission marks a pending submission as rejected. The reason is shown
+// to the user in their status.
+func (s *Storage) RejectSubmission(id int64, reviewer string, reason string) error {
+	const op = "storage.postgresql.RejectSubmission"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, _, err := lockPendingSubmission(tx, id); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE task_submissions
+		SET status = $2, reason = $3, reviewed_by = $4, reviewed_at = $5
+		WHERE id = $1`, id, models.SubmissionRejected, reason, reviewer, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("%s: update submission: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return nil
+}
+
+func lockPendingSubmission(tx *sql.Tx, id int64) (userID int64, taskID int64, err error) {
+	const op = "storage.postgresql.lockPendingSubmission"
+
+	var status string
+	err = tx.QueryRow(`
+		SELECT user_id, task_id, status FROM task_submissions
+		WHERE id = $1
+		FOR UPDATE`, id).Scan(&userID, &taskID, &status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, 0, storage.ErrSubmissionNotFound
+		}
+		return 0, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if status != models.SubmissionPending {
+		return 0, 0, storage.ErrSubmissionReviewed
+	}
+	return userID, taskID, nil
+}
@@ -0,0 +1,286 @@
+package postgres
+
+import (
+	"database/sql"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ListUserTasks returns every active catalog task with the user's completion
+// state, evaluated with the same rules as CompleteTask.
+func (s *Storage) ListUserTasks(userID int64) ([]models.UserTask, error) {
+	const op = "storage.postgresql.ListUserTasks"
+
+	var exists bool
+	err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, userID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: select user: %w", op, err)
+	}
+	if !exists {
+		return nil, storage.ErrUserNotFound
+	}
+
+	rows, err := s.db.Query(`
+		SELECT `+taskColumns+`,
+			st.last_completed_at, COALESCE(st.streak, 0), COALESCE(st.completions, 0), COALESCE(pr.current, 0),
+			EXISTS (
+				SELECT 1 FROM task_submissions sub
+				WHERE sub.user_id = $1 AND sub.task_id = t.id AND sub.status = 'pending'
+			)
+		FROM tasks t
+		LEFT JOIN user_task_states st ON st.task_id = t.id AND st.user_id = $1
+		LEFT JOIN user_task_progress pr ON pr.task_id = t.id AND pr.user_id = $1
+		WHERE t.active
+		ORDER BY t.id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: select tasks: %w", op, err)
+	}
+	defer rows.Close()
+
+	var tasks []models.UserTask
+	pending := make(map[int64]bool)
+	for rows.Next() {
+		var task models.UserTask
+		var last sql.NullTime
+		var current int64
+		var isPending bool
+		err := scanTask(rows, &task.Task, &last, &task.Streak, &task.Completions, &current, &isPending)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan task: %w", op, err)
+		}
+		if last.Valid {
+			task.LastCompletedAt = &last.Time
+		}
+		if task.ProgressBased() {
+			task.Progress = &models.Progress{
+				TaskId:      task.Id,
+				TaskSlug:    task.Slug,
+				Current:     current,
+				Target:      task.TargetValue,
+				CompletedAt: task.LastCompletedAt,
+			}
+		}
+		pending[task.Id] = isPending
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	prerequisites, err := s.prerequisiteSlugs()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	completed, err := s.completedTaskSlugs(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now().UTC()
+	for i := range tasks {
+		task := &tasks[i]
+		task.Prerequisites = prerequisites[task.Id]
+		for _, slug := range task.Prerequisites {
+			if !completed[slug] {
+				task.MissingPrerequisites = append(task.MissingPrerequisites, slug)
+			}
+		}
+		task.State = userTaskState(task, pending[task.Id], now)
+	}
+	return tasks, nil
+}
+
+func userTaskState(task *models.UserTask, pending bool, now time.Time) string {
+	if task.LastCompletedAt == nil || task.Policy().Repeatable() {
+		if state, ok := task.Window(now); !ok {
+			return state
+		}
+	}
+	if task.LastCompletedAt == nil && task.MaxCompletions > 0 && task.CompletionsCount >= task.MaxCompletions {
+		return models.TaskExhausted
+	}
+	if len(task.MissingPrerequisites) > 0 {
+		return models.TaskLocked
+	}
+	if pending {
+		return models.TaskPending
+	}
+	if task.LastCompletedAt == nil {
+		return models.TaskAvailable
+	}
+
+	policy := task.Policy()
+	if !policy.StreakAlive(*task.LastCompletedAt, now) {
+		task.Streak = 0
+	}
+	next, ok := policy.NextAvailable(*task.LastCompletedAt)
+	if !ok {
+		return models.TaskCompleted
+	}
+	if now.Before(next) {
+		task.AvailableAt = &next
+		return models.TaskCooldown
+	}
+	return models.TaskAvailable
+}
+
+// prerequisiteSlugs maps each task ID to the slugs of its prerequisites.
+func (s *Storage) prerequisiteSlugs() (map[int64][]string, error) {
+	rows, err := s.db.Query(`
+		SELECT p.task_id, t.slug
+		FROM task_prerequisites p
+		JOIN tasks t ON t.id = p.prerequisite_id
+		ORDER BY p.task_id, t.id`)
+	if err != nil {
+		return nil, fmt.Errorf("select prerequisites: %w", err)
+	}
+	defer rows.Close()
+
+	prerequisites := make(map[int64][]string)
+	for rows.Next() {
+		var taskID int64
+		var slug string
+		if err := rows.Scan(&taskID, &slug); err != nil {
+			return nil, fmt.Errorf("scan prerequisite: %w", err)
+		}
+		prerequisites[taskID] = append(prerequisites[taskID], slug)
+	}
+	return prerequisites, rows.Err()
+}
+
+// completedTaskSlugs returns the slugs of every task the user completed at
+// least once, including inactive ones.
+func (s *Storage) completedTaskSlugs(userID int64) (map[string]bool, error) {
+	rows, err := s.db.Query(`
+		SELECT t.slug
+		FROM user_task_states st
+		JOIN tasks t ON t.id = st.task_id
+		WHERE st.user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("select completed tasks: %w", err)
+	}
+	defer rows.Close()
+
+	completed := make(map[string]bool)
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, fmt.Errorf("scan completed task: %w", err)
+		}
+		completed[slug] = true
+	}
+	return completed, rows.Err()
+}
+
+// ListCatalog returns every catalog task, including inactive ones, with the
+// slugs of its prerequisites.
+func (s *Storage) ListCatalog() ([]models.CatalogTask, error) {
+	const op = "storage.postgresql.ListCatalog"
+
+	rows, err := s.db.Query(`SELECT ` + taskColumns + ` FROM tasks t ORDER BY t.id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: select tasks: %w", op, err)
+	}
+	defer rows.Close()
+
+	var tasks []models.CatalogTask
+	for rows.Next() {
+		var task models.CatalogTask
+		if err := scanTask(rows, &task.Task); err != nil {
+			return nil, fmt.Errorf("%s: scan task: %w", op, err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	prerequisites, err := s.prerequisiteSlugs()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for i := range tasks {
+		tasks[i].Prerequisites = prerequisites[tasks[i].Id]
+	}
+	return tasks, nil
+}
+
+// SyncCatalog upserts tasks by slug and replaces their prerequisites in one
+// transaction. Tasks missing from the list are left untouched.
+func (s *Storage) SyncCatalog(tasks []models.CatalogTask) error {
+	const op = "storage.postgresql.SyncCatalog"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	ids := make(map[string]int64, len(tasks))
+	for _, task := range tasks {
+		var id int64
+		err := tx.QueryRow(`
+			INSERT INTO tasks (slug, title, type, target, reward, active, recurrence, cooldown_seconds, streak_bonus,
+				starts_at, ends_at, max_completions, counter, target_value, expiry_days)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, 0), $13, $14, $15)
+			ON CONFLICT (slug) DO UPDATE SET
+				title = EXCLUDED.title,
+				type = EXCLUDED.type,
+				target = EXCLUDED.target,
+				reward = EXCLUDED.reward,
+				active = EXCLUDED.active,
+				recurrence = EXCLUDED.recurrence,
+				cooldown_seconds = EXCLUDED.cooldown_seconds,
+				streak_bonus = EXCLUDED.streak_bonus,
+				starts_at = EXCLUDED.starts_at,
+				ends_at = EXCLUDED.ends_at,
+				max_completions = EXCLUDED.max_completions,
+				counter = EXCLUDED.counter,
+				target_value = EXCLUDED.target_value,
+				expiry_days = EXCLUDED.expiry_days,
+				updated_at = CURRENT_TIMESTAMP
+			RETURNING id`,
+			task.Slug, task.Title, task.Type, task.Target, task.Reward, task.Active, task.Recurrence, task.CooldownSeconds, task.StreakBonus,
+			task.StartsAt, task.EndsAt, task.MaxCompletions, task.Counter, task.TargetValue, task.ExpiryDays,
+		).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("%s: upsert task %q: %w", op, task.Slug, err)
+		}
+		ids[task.Slug] = id
+
+		if err := setTaskRewards(tx, id, task.Rewards); err != nil {
+			return fmt.Errorf("task %q: %w", task.Slug, err)
+		}
+	}
+
+	prerequisites := make(map[int64][]int64, len(tasks))
+	for _, task := range tasks {
+		prerequisiteIDs := []int64{}
+		for _, slug := range task.Prerequisites {
+			id, ok := ids[slug]
+			if !ok {
+				err := tx.QueryRow(`SELECT id FROM tasks WHERE slug = $1`, slug).Scan(&id)
+				if errors.Is(err, sql.ErrNoRows) {
+					return fmt.Errorf("%w: prerequisite %q of %q", storage.ErrTaskNotFound, slug, task.Slug)
+				}
+				if err != nil {
+					return fmt.Errorf("%s: select prerequisite %q: %w", op, slug, err)
+				}
+			}
+			prerequisiteIDs = append(prerequisiteIDs, id)
+		}
+		prerequisites[ids[task.Slug]] = prerequisiteIDs
+	}
+	if err := setPrerequisites(tx, prerequisites); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return nil
+}
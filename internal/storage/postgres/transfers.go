@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// transferAttempts bounds how many times a transfer is retried after a
+// serialization failure before ErrTransferConflict is returned.
+const transferAttempts = 3
+
+// Transfer moves amount points from one user to another in a serializable
+// transaction and records a debit and a credit entry in the ledger.
+func (s *Storage) Transfer(fromID, toID, amount int64, memo string, limits models.TransferLimits) (*models.Transfer, error) {
+	const op = "storage.postgresql.Transfer"
+
+	if fromID == toID {
+		return nil, storage.ErrSelfTransfer
+	}
+
+	for attempt := 0; attempt < transferAttempts; attempt++ {
+		transfer, err := s.transfer(fromID, toID, amount, memo, limits)
+		if isSerializationFailure(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return transfer, nil
+	}
+	return nil, storage.ErrTransferConflict
+}
+
+func (s *Storage) transfer(fromID, toID, amount int64, memo string, limits models.TransferLimits) (*models.Transfer, error) {
+	tx, err := s.db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Both rows are locked in id order so opposite transfers cannot deadlock.
+	rows, err := tx.Query(`SELECT id, points FROM users WHERE id IN ($1, $2) ORDER BY id FOR UPDATE`, fromID, toID)
+	if err != nil {
+		return nil, fmt.Errorf("lock users: %w", err)
+	}
+	balances := make(map[int64]int64, 2)
+	for rows.Next() {
+		var id, points int64
+		if err := rows.Scan(&id, &points); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		balances[id] = points
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("lock users: %w", err)
+	}
+
+	balance, ok := balances[fromID]
+	if !ok {
+		return nil, storage.ErrUserNotFound
+	}
+	if _, ok := balances[toID]; !ok {
+		return nil, storage.ErrRecipientNotFound
+	}
+	if balance < amount {
+		return nil, storage.ErrInsufficientFunds
+	}
+
+	now := time.Now().UTC()
+	if limits.DailyAmount > 0 || limits.DailyCount > 0 {
+		day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+		var sent, count int64
+		err = tx.QueryRow(`
+			SELECT COALESCE(SUM(-delta), 0), COUNT(*)
+			FROM points_transactions
+			WHERE user_id = $1 AND reason = $2 AND delta < 0 AND created_at >= $3`,
+			fromID, models.ReasonTransfer, day,
+		).Scan(&sent, &count)
+		if err != nil {
+			return nil, fmt.Errorf("select daily transfers: %w", err)
+		}
+		if limits.DailyAmount > 0 && sent+amount > limits.DailyAmount {
+			return nil, storage.ErrTransferLimitExceeded
+		}
+		if limits.DailyCount > 0 && count+1 > limits.DailyCount {
+			return nil, storage.ErrTransferLimitExceeded
+		}
+	}
+
+	// The recipient gets the sender's credits with their expiry, so that a
+	// transfer cannot turn expiring points into points that never expire.
+	parts, err := creditsToSpend(tx, fromID, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := postEntry(tx, models.LedgerEntry{
+		UserId:         fromID,
+		Delta:          -amount,
+		Reason:         models.ReasonTransfer,
+		CounterpartyId: &toID,
+		Memo:           memo,
+		CreatedAt:      now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, part := range parts {
+		_, err = postEntry(tx, models.LedgerEntry{
+			UserId:         toID,
+			Delta:          part.amount,
+			Reason:         models.ReasonTransfer,
+			CounterpartyId: &fromID,
+			Memo:           memo,
+			ExpiresAt:      part.expiresAt,
+			CreatedAt:      now,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	return &models.Transfer{
+		Id:        id,
+		FromId:    fromID,
+		ToId:      toID,
+		Amount:    amount,
+		Memo:      memo,
+		Balance:   balance - amount,
+		CreatedAt: now,
+	}, nil
+}
+
+func isSerializationFailure(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "40001"
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"database/sql"
+	"denet/internal/lib/models"
+	"denet/internal/storage"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// SaveWalletChallenge stores challenge, replacing any outstanding challenge
+// of the user.
+func (s *Storage) SaveWalletChallenge(challenge models.WalletChallenge) error {
+	const op = "storage.postgresql.SaveWalletChallenge"
+
+	_, err := s.db.Exec(`
+		INSERT INTO wallet_challenges (user_id, address, nonce, message, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET address = EXCLUDED.address, nonce = EXCLUDED.nonce, message = EXCLUDED.message,
+			expires_at = EXCLUDED.expires_at, created_at = EXCLUDED.created_at`,
+		challenge.UserId, challenge.Address, challenge.Nonce, challenge.Message, challenge.ExpiresAt, challenge.CreatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return storage.ErrUserNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetWalletChallenge returns the user's outstanding challenge if it has not
+// expired at now.
+func (s *Storage) GetWalletChallenge(userID int64, now time.Time) (*models.WalletChallenge, error) {
+	const op = "storage.postgresql.GetWalletChallenge"
+
+	challenge := &models.WalletChallenge{UserId: userID}
+	err := s.db.QueryRow(`
+		SELECT address, nonce, message, expires_at, created_at
+		FROM wallet_challenges
+		WHERE user_id = $1 AND expires_at > $2`, userID, now,
+	).Scan(&challenge.Address, &challenge.Nonce, &challenge.Message, &challenge.ExpiresAt, &challenge.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrChallengeNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return challenge, nil
+}
+
+// LinkWallet consumes the challenge identified by nonce and stores its
+// address on the user. A challenge can only be used once.
+func (s *Storage) LinkWallet(userID int64, nonce string, now time.Time) (string, error) {
+	const op = "storage.postgresql.LinkWallet"
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var address string
+	err = tx.QueryRow(`
+		DELETE FROM wallet_challenges
+		WHERE user_id = $1 AND nonce = $2 AND expires_at > $3
+		RETURNING address`, userID, nonce, now,
+	).Scan(&address)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", storage.ErrChallengeNotFound
+		}
+		return "", fmt.Errorf("%s: consume challenge: %w", op, err)
+	}
+
+	_, err = tx.Exec(`UPDATE users SET wallet_address = $1 WHERE id = $2`, address, userID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return "", storage.ErrWalletTaken
+		}
+		return "", fmt.Errorf("%s: update user: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return address, nil
+}
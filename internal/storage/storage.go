@@ -1,8 +1,88 @@
 package storage
 
-import "errors"
+import (
+	"denet/internal/lib/models"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
 var (
 	ErrUserNotFound = errors.New("user not found")
 	ErrUserExists   = errors.New("user exists")
+	ErrTaskNotFound = errors.New("task not found")
+	ErrTaskExists   = errors.New("task exists")
+
+	ErrAccountTaken = errors.New("external account is linked to another user")
+
+	ErrSelfReferral     = errors.New("user cannot refer themselves")
+	ErrReferrerNotFound = errors.New("referrer not found")
+	ErrReferralSet      = errors.New("referrer is already set")
+
+	ErrTaskAlreadyCompleted = errors.New("task already completed")
+	ErrTaskOnCooldown       = errors.New("task is on cooldown")
+	ErrTaskNotStarted       = errors.New("task has not started yet")
+	ErrTaskExpired          = errors.New("task has expired")
+	ErrTaskExhausted        = errors.New("task completion limit reached")
+	ErrTaskAutoCompleted    = errors.New("task completes automatically")
+	ErrPrerequisitesMissing = errors.New("task prerequisites are not completed")
+	ErrPrerequisiteCycle    = errors.New("task prerequisites form a cycle")
+
+	ErrSubmissionNotFound = errors.New("submission not found")
+	ErrSubmissionPending  = errors.New("submission is already pending review")
+	ErrSubmissionReviewed = errors.New("submission is already reviewed")
+
+	ErrSelfTransfer          = errors.New("user cannot transfer points to themselves")
+	ErrRecipientNotFound     = errors.New("recipient not found")
+	ErrInsufficientFunds     = errors.New("insufficient funds")
+	ErrTransferLimitExceeded = errors.New("daily transfer limit exceeded")
+	ErrTransferConflict      = errors.New("transfer conflicted with a concurrent update")
+
+	ErrRewardNotFound      = errors.New("reward not found")
+	ErrRewardExists        = errors.New("reward exists")
+	ErrOutOfStock          = errors.New("reward is out of stock")
+	ErrRedemptionNotFound  = errors.New("redemption not found")
+	ErrRedemptionFinalized = errors.New("redemption is already fulfilled or cancelled")
+
+	ErrUnknownCurrency = errors.New("unknown currency")
+
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+	ErrAirdropNotFound  = errors.New("airdrop not found")
+	ErrClaimNotFound    = errors.New("user has no claim in the airdrop")
+
+	ErrChallengeNotFound = errors.New("wallet challenge not found or expired")
+	ErrWalletTaken       = errors.New("wallet is linked to another user")
 )
+
+// CooldownError is returned when a recurring task is completed again before
+// its cooldown has passed. It matches ErrTaskOnCooldown with errors.Is.
+type CooldownError struct {
+	Until time.Time
+}
+
+func (e *CooldownError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrTaskOnCooldown, e.Until.Format(time.RFC3339))
+}
+
+func (e *CooldownError) Is(target error) bool {
+	return target == ErrTaskOnCooldown
+}
+
+// PrerequisitesError is returned when a task is completed before all of its
+// prerequisites. It matches ErrPrerequisitesMissing with errors.Is.
+type PrerequisitesError struct {
+	Missing []models.Task
+}
+
+func (e *PrerequisitesError) Error() string {
+	slugs := make([]string, 0, len(e.Missing))
+	for _, task := range e.Missing {
+		slugs = append(slugs, task.Slug)
+	}
+	return fmt.Sprintf("%s: %s", ErrPrerequisitesMissing, strings.Join(slugs, ", "))
+}
+
+func (e *PrerequisitesError) Is(target error) bool {
+	return target == ErrPrerequisitesMissing
+}
@@ -0,0 +1,34 @@
+package verifier
+
+import (
+	"context"
+	"denet/internal/lib/models"
+	"sync"
+)
+
+// Call records a single Fake.Verify invocation.
+type Call struct {
+	UserID int64
+	Task   models.Task
+}
+
+// Fake is a Verifier for tests. It returns Err for every call and records the
+// calls it received, so handlers can be exercised without network access.
+type Fake struct {
+	mu    sync.Mutex
+	Err   error
+	calls []Call
+}
+
+func (f *Fake) Verify(_ context.Context, userID int64, task models.Task) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{UserID: userID, Task: task})
+	return f.Err
+}
+
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
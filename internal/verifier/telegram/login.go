@@ -0,0 +1,68 @@
+package telegram
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidLogin = errors.New("invalid telegram login")
+	ErrLoginExpired = errors.New("telegram login has expired")
+)
+
+// Login is the payload the Telegram Login Widget hands to the frontend after
+// the user authorised the bot.
+type Login struct {
+	Id        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Username  string `json:"username"`
+	PhotoURL  string `json:"photo_url"`
+	AuthDate  int64  `json:"auth_date"`
+	Hash      string `json:"hash"`
+}
+
+// CheckLogin verifies that login was signed by Telegram for the bot of
+// token and was issued less than maxAge before now. See
+// https://core.telegram.org/widgets/login#checking-authorization.
+func CheckLogin(token string, login Login, now time.Time, maxAge time.Duration) error {
+	if token == "" || login.Id == 0 || login.Hash == "" {
+		return ErrInvalidLogin
+	}
+
+	fields := map[string]string{
+		"id":         strconv.FormatInt(login.Id, 10),
+		"first_name": login.FirstName,
+		"last_name":  login.LastName,
+		"username":   login.Username,
+		"photo_url":  login.PhotoURL,
+		"auth_date":  strconv.FormatInt(login.AuthDate, 10),
+	}
+	pairs := make([]string, 0, len(fields))
+	for key, value := range fields {
+		if value != "" {
+			pairs = append(pairs, key+"="+value)
+		}
+	}
+	sort.Strings(pairs)
+
+	secret := sha256.Sum256([]byte(token))
+	mac := hmac.New(sha256.New, secret[:])
+	mac.Write([]byte(strings.Join(pairs, "\n")))
+
+	hash, err := hex.DecodeString(login.Hash)
+	if err != nil || !hmac.Equal(mac.Sum(nil), hash) {
+		return ErrInvalidLogin
+	}
+
+	if now.Sub(time.Unix(login.AuthDate, 0)) > maxAge {
+		return ErrLoginExpired
+	}
+	return nil
+}
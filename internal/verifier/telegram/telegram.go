@@ -0,0 +1,105 @@
+package telegram
+
+import (
+	"context"
+	"denet/internal/config"
+	"denet/internal/lib/models"
+	"denet/internal/verifier"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// AccountProvider resolves the Telegram account linked to a user.
+type AccountProvider interface {
+	GetTelegramID(userID int64) (int64, error)
+}
+
+// Verifier confirms channel membership through the Bot API getChatMember
+// method. The task target, when set, overrides the configured channel.
+type Verifier struct {
+	client   *http.Client
+	apiURL   string
+	token    string
+	channel  string
+	accounts AccountProvider
+}
+
+func New(cfg config.Telegram, accounts AccountProvider) *Verifier {
+	return &Verifier{
+		client:   &http.Client{Timeout: cfg.Timeout},
+		apiURL:   strings.TrimRight(cfg.APIURL, "/"),
+		token:    cfg.BotToken,
+		channel:  cfg.Channel,
+		accounts: accounts,
+	}
+}
+
+type chatMemberResponse struct {
+	Ok          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+	Result      struct {
+		Status   string `json:"status"`
+		IsMember bool   `json:"is_member"`
+	} `json:"result"`
+}
+
+func (v *Verifier) Verify(ctx context.Context, userID int64, task models.Task) error {
+	const op = "verifier.telegram.Verify"
+
+	telegramID, err := v.accounts.GetTelegramID(userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if telegramID == 0 {
+		return verifier.ErrAccountNotLinked
+	}
+
+	channel := v.channel
+	if task.Target != "" {
+		channel = task.Target
+	}
+
+	query := url.Values{}
+	query.Set("chat_id", channel)
+	query.Set("user_id", strconv.FormatInt(telegramID, 10))
+	endpoint := fmt.Sprintf("%s/bot%s/getChatMember?%s", v.apiURL, v.token, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	res, err := v.client.Do(req)
+	if err != nil {
+		// The URL contains the bot token, so the transport error is not wrapped.
+		return fmt.Errorf("%s: %w: request failed", op, verifier.ErrUpstreamUnavailable)
+	}
+	defer res.Body.Close()
+
+	var body chatMemberResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return fmt.Errorf("%s: %w: status %d: %v", op, verifier.ErrUpstreamUnavailable, res.StatusCode, err)
+	}
+
+	if !body.Ok {
+		// Telegram answers 400 "user not found" for users that never joined.
+		if body.ErrorCode == http.StatusBadRequest && strings.Contains(strings.ToLower(body.Description), "user not found") {
+			return verifier.ErrNotMember
+		}
+		return fmt.Errorf("%s: %w: %d %s", op, verifier.ErrUpstreamUnavailable, body.ErrorCode, body.Description)
+	}
+
+	switch body.Result.Status {
+	case "creator", "administrator", "member":
+		return nil
+	case "restricted":
+		if body.Result.IsMember {
+			return nil
+		}
+	}
+	return verifier.ErrNotMember
+}
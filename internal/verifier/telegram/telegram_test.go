@@ -0,0 +1,165 @@
+package telegram
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"denet/internal/config"
+	"denet/internal/lib/models"
+	"denet/internal/verifier"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type accounts map[int64]int64
+
+func (a accounts) GetTelegramID(userID int64) (int64, error) {
+	return a[userID], nil
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int64
+		status   int
+		body     string
+		want     error
+		requests int
+	}{
+		{
+			name:     "member",
+			userID:   1,
+			status:   http.StatusOK,
+			body:     `{"ok":true,"result":{"status":"member"}}`,
+			requests: 1,
+		},
+		{
+			name:     "left",
+			userID:   1,
+			status:   http.StatusOK,
+			body:     `{"ok":true,"result":{"status":"left"}}`,
+			want:     verifier.ErrNotMember,
+			requests: 1,
+		},
+		{
+			name:     "kicked",
+			userID:   1,
+			status:   http.StatusOK,
+			body:     `{"ok":true,"result":{"status":"kicked"}}`,
+			want:     verifier.ErrNotMember,
+			requests: 1,
+		},
+		{
+			name:     "user not found",
+			userID:   1,
+			status:   http.StatusBadRequest,
+			body:     `{"ok":false,"error_code":400,"description":"Bad Request: user not found"}`,
+			want:     verifier.ErrNotMember,
+			requests: 1,
+		},
+		{
+			name:   "not linked",
+			userID: 2,
+			want:   verifier.ErrAccountNotLinked,
+		},
+		{
+			name:     "upstream error",
+			userID:   1,
+			status:   http.StatusBadGateway,
+			body:     `<html>502 Bad Gateway</html>`,
+			want:     verifier.ErrUpstreamUnavailable,
+			requests: 1,
+		},
+		{
+			name:     "upstream error with json body",
+			userID:   1,
+			status:   http.StatusInternalServerError,
+			body:     `{"ok":false,"error_code":500,"description":"Internal Server Error"}`,
+			want:     verifier.ErrUpstreamUnavailable,
+			requests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if r.URL.Path != "/bottoken/getChatMember" {
+					t.Errorf("path = %q", r.URL.Path)
+				}
+				if got := r.URL.Query().Get("chat_id"); got != "@denet" {
+					t.Errorf("chat_id = %q, want @denet", got)
+				}
+				if got := r.URL.Query().Get("user_id"); got != "1001" {
+					t.Errorf("user_id = %q, want 1001", got)
+				}
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer srv.Close()
+
+			v := New(config.Telegram{BotToken: "token", Channel: "@denet", APIURL: srv.URL, Timeout: time.Second}, accounts{1: 1001})
+			err := v.Verify(context.Background(), tt.userID, models.Task{Id: 1, Type: verifier.TypeTelegramSubscription})
+
+			if tt.want == nil && err != nil {
+				t.Fatalf("Verify() = %v, want nil", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("Verify() = %v, want %v", err, tt.want)
+			}
+			if requests != tt.requests {
+				t.Errorf("requests = %d, want %d", requests, tt.requests)
+			}
+		})
+	}
+}
+
+func TestVerifyTaskTargetOverridesChannel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("chat_id"); got != "@other" {
+			t.Errorf("chat_id = %q, want @other", got)
+		}
+		fmt.Fprint(w, `{"ok":true,"result":{"status":"administrator"}}`)
+	}))
+	defer srv.Close()
+
+	v := New(config.Telegram{BotToken: "token", Channel: "@denet", APIURL: srv.URL, Timeout: time.Second}, accounts{1: 1001})
+	if err := v.Verify(context.Background(), 1, models.Task{Id: 1, Target: "@other"}); err != nil {
+		t.Fatalf("Verify() = %v, want nil", err)
+	}
+}
+
+func sign(token string, dataCheck string) string {
+	secret := sha256.Sum256([]byte(token))
+	mac := hmac.New(sha256.New, secret[:])
+	mac.Write([]byte(dataCheck))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestCheckLogin(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	login := Login{Id: 1001, FirstName: "Ann", Username: "ann", AuthDate: now.Unix() - 60}
+	login.Hash = sign("token", "auth_date=1699999940\nfirst_name=Ann\nid=1001\nusername=ann")
+
+	if err := CheckLogin("token", login, now, time.Hour); err != nil {
+		t.Fatalf("CheckLogin() = %v, want nil", err)
+	}
+	if err := CheckLogin("other", login, now, time.Hour); !errors.Is(err, ErrInvalidLogin) {
+		t.Errorf("wrong token: CheckLogin() = %v, want %v", err, ErrInvalidLogin)
+	}
+	if err := CheckLogin("token", login, now.Add(2*time.Hour), time.Hour); !errors.Is(err, ErrLoginExpired) {
+		t.Errorf("old login: CheckLogin() = %v, want %v", err, ErrLoginExpired)
+	}
+
+	tampered := login
+	tampered.Id = 1002
+	if err := CheckLogin("token", tampered, now, time.Hour); !errors.Is(err, ErrInvalidLogin) {
+		t.Errorf("tampered: CheckLogin() = %v, want %v", err, ErrInvalidLogin)
+	}
+}
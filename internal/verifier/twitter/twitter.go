@@ -0,0 +1,200 @@
+package twitter
+
+import (
+	"context"
+	"denet/internal/config"
+	"denet/internal/lib/models"
+	"denet/internal/verifier"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AccountProvider resolves the X account linked to a user.
+type AccountProvider interface {
+	GetTwitterID(userID int64) (string, error)
+}
+
+// Verifier checks follow, retweet and like tasks against the X API v2. The
+// task target holds the account ID to follow or the post ID to engage with.
+// Definitive answers are cached per user and task for the configured TTL to
+// stay inside the API rate limits.
+type Verifier struct {
+	client   *http.Client
+	apiURL   string
+	token    string
+	maxPages int
+	ttl      time.Duration
+	accounts AccountProvider
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[cacheKey]cacheEntry
+}
+
+type cacheKey struct {
+	userID int64
+	taskID int64
+}
+
+type cacheEntry struct {
+	err       error
+	expiresAt time.Time
+}
+
+func New(cfg config.Twitter, accounts AccountProvider) *Verifier {
+	maxPages := cfg.MaxPages
+	if maxPages <= 0 {
+		maxPages = 1
+	}
+	return &Verifier{
+		client:   &http.Client{Timeout: cfg.Timeout},
+		apiURL:   strings.TrimRight(cfg.APIURL, "/"),
+		token:    cfg.BearerToken,
+		maxPages: maxPages,
+		ttl:      cfg.CacheTTL,
+		accounts: accounts,
+		now:      time.Now,
+		cache:    make(map[cacheKey]cacheEntry),
+	}
+}
+
+type usersResponse struct {
+	Data []struct {
+		Id string `json:"id"`
+	} `json:"data"`
+	Meta struct {
+		NextToken string `json:"next_token"`
+	} `json:"meta"`
+}
+
+func (v *Verifier) Verify(ctx context.Context, userID int64, task models.Task) error {
+	const op = "verifier.twitter.Verify"
+
+	key := cacheKey{userID: userID, taskID: task.Id}
+	if entry, ok := v.cached(key); ok {
+		return entry.err
+	}
+
+	twitterID, err := v.accounts.GetTwitterID(userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if twitterID == "" {
+		return verifier.ErrAccountNotLinked
+	}
+
+	path, err := listPath(task)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = v.findUser(ctx, path, twitterID)
+	if err == nil || errors.Is(err, verifier.ErrNotVerified) {
+		v.store(key, err)
+	}
+	if err != nil && !errors.Is(err, verifier.ErrNotVerified) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return err
+}
+
+// listPath returns the API path listing the users that did what the task asks.
+func listPath(task models.Task) (string, error) {
+	if task.Target == "" {
+		return "", fmt.Errorf("task %d has no target", task.Id)
+	}
+	target := url.PathEscape(task.Target)
+
+	switch task.Type {
+	case verifier.TypeTwitterFollow:
+		return "/2/users/" + target + "/followers", nil
+	case verifier.TypeTwitterRetweet:
+		return "/2/tweets/" + target + "/retweeted_by", nil
+	case verifier.TypeTwitterLike:
+		return "/2/tweets/" + target + "/liking_users", nil
+	}
+	return "", fmt.Errorf("%w: %q", verifier.ErrUnknownType, task.Type)
+}
+
+// findUser pages through the user list at path looking for twitterID. It
+// returns ErrNotVerified when the user is not found within maxPages.
+func (v *Verifier) findUser(ctx context.Context, path string, twitterID string) error {
+	var nextToken string
+	for page := 0; page < v.maxPages; page++ {
+		query := url.Values{}
+		query.Set("max_results", "100")
+		if nextToken != "" {
+			query.Set("pagination_token", nextToken)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.apiURL+path+"?"+query.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+v.token)
+
+		res, err := v.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("%w: %v", verifier.ErrUpstreamUnavailable, err)
+		}
+
+		var body usersResponse
+		err = json.NewDecoder(res.Body).Decode(&body)
+		res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("%w: status %d", verifier.ErrUpstreamUnavailable, res.StatusCode)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: decode response: %v", verifier.ErrUpstreamUnavailable, err)
+		}
+
+		for _, user := range body.Data {
+			if user.Id == twitterID {
+				return nil
+			}
+		}
+		if body.Meta.NextToken == "" {
+			break
+		}
+		nextToken = body.Meta.NextToken
+	}
+	return verifier.ErrNotVerified
+}
+
+func (v *Verifier) cached(key cacheKey) (cacheEntry, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entry, ok := v.cache[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	if !v.now().Before(entry.expiresAt) {
+		delete(v.cache, key)
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (v *Verifier) store(key cacheKey, err error) {
+	if v.ttl <= 0 {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+	for k, entry := range v.cache {
+		if !now.Before(entry.expiresAt) {
+			delete(v.cache, k)
+		}
+	}
+	v.cache[key] = cacheEntry{err: err, expiresAt: now.Add(v.ttl)}
+}
@@ -0,0 +1,189 @@
+package twitter
+
+import (
+	"context"
+	"denet/internal/config"
+	"denet/internal/lib/models"
+	"denet/internal/verifier"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type accounts map[int64]string
+
+func (a accounts) GetTwitterID(userID int64) (string, error) {
+	return a[userID], nil
+}
+
+// fakeAPI serves canned user lists split into pages and counts requests.
+type fakeAPI struct {
+	t     *testing.T
+	lists map[string][][]string
+
+	mu       sync.Mutex
+	requests int
+	status   int
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests++
+	status := f.status
+	f.mu.Unlock()
+
+	if got := r.Header.Get("Authorization"); got != "Bearer token" {
+		f.t.Errorf("Authorization = %q", got)
+	}
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	pages, ok := f.lists[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	page := 0
+	if token := r.URL.Query().Get("pagination_token"); token != "" {
+		page = int(token[0] - '0')
+	}
+
+	var body usersResponse
+	for _, id := range pages[page] {
+		body.Data = append(body.Data, struct {
+			Id string `json:"id"`
+		}{Id: id})
+	}
+	if page+1 < len(pages) {
+		body.Meta.NextToken = string(rune('0' + page + 1))
+	}
+	json.NewEncoder(w).Encode(body)
+}
+
+func (f *fakeAPI) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+func newVerifier(t *testing.T, api *fakeAPI, maxPages int, ttl time.Duration) *Verifier {
+	t.Helper()
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+
+	cfg := config.Twitter{BearerToken: "token", APIURL: srv.URL, Timeout: time.Second, CacheTTL: ttl, MaxPages: maxPages}
+	return New(cfg, accounts{1: "42", 2: "99"})
+}
+
+func TestVerifyPaths(t *testing.T) {
+	api := &fakeAPI{t: t, lists: map[string][][]string{
+		"/2/users/100/followers":     {{"42"}},
+		"/2/tweets/200/retweeted_by": {{"42"}},
+		"/2/tweets/300/liking_users": {{"42"}},
+	}}
+	v := newVerifier(t, api, 1, 0)
+
+	tasks := []models.Task{
+		{Id: 1, Type: verifier.TypeTwitterFollow, Target: "100"},
+		{Id: 2, Type: verifier.TypeTwitterRetweet, Target: "200"},
+		{Id: 3, Type: verifier.TypeTwitterLike, Target: "300"},
+	}
+	for _, task := range tasks {
+		if err := v.Verify(context.Background(), 1, task); err != nil {
+			t.Errorf("%s: Verify() = %v, want nil", task.Type, err)
+		}
+		if err := v.Verify(context.Background(), 2, task); !errors.Is(err, verifier.ErrNotVerified) {
+			t.Errorf("%s: Verify() = %v, want %v", task.Type, err, verifier.ErrNotVerified)
+		}
+	}
+}
+
+func TestVerifyPagination(t *testing.T) {
+	api := &fakeAPI{t: t, lists: map[string][][]string{
+		"/2/users/100/followers": {{"1", "2"}, {"3", "42"}, {"99"}},
+	}}
+	task := models.Task{Id: 1, Type: verifier.TypeTwitterFollow, Target: "100"}
+
+	v := newVerifier(t, api, 3, 0)
+	if err := v.Verify(context.Background(), 1, task); err != nil {
+		t.Fatalf("Verify() = %v, want nil", err)
+	}
+	if got := api.count(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+
+	// The last page is beyond maxPages, so the user on it is not found.
+	api.requests = 0
+	v = newVerifier(t, api, 2, 0)
+	if err := v.Verify(context.Background(), 2, task); !errors.Is(err, verifier.ErrNotVerified) {
+		t.Fatalf("Verify() = %v, want %v", err, verifier.ErrNotVerified)
+	}
+	if got := api.count(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestVerifyCache(t *testing.T) {
+	api := &fakeAPI{t: t, lists: map[string][][]string{
+		"/2/users/100/followers": {{"42"}},
+	}}
+	task := models.Task{Id: 1, Type: verifier.TypeTwitterFollow, Target: "100"}
+
+	v := newVerifier(t, api, 1, time.Minute)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	v.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if err := v.Verify(context.Background(), 1, task); err != nil {
+			t.Fatalf("Verify() = %v, want nil", err)
+		}
+		if err := v.Verify(context.Background(), 2, task); !errors.Is(err, verifier.ErrNotVerified) {
+			t.Fatalf("Verify() = %v, want %v", err, verifier.ErrNotVerified)
+		}
+	}
+	if got := api.count(); got != 2 {
+		t.Errorf("requests within TTL = %d, want 2", got)
+	}
+
+	now = now.Add(time.Minute)
+	if err := v.Verify(context.Background(), 1, task); err != nil {
+		t.Fatalf("Verify() = %v, want nil", err)
+	}
+	if got := api.count(); got != 3 {
+		t.Errorf("requests after TTL = %d, want 3", got)
+	}
+}
+
+func TestVerifyUpstreamErrorNotCached(t *testing.T) {
+	api := &fakeAPI{t: t, status: http.StatusTooManyRequests}
+	task := models.Task{Id: 1, Type: verifier.TypeTwitterFollow, Target: "100"}
+	v := newVerifier(t, api, 1, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if err := v.Verify(context.Background(), 1, task); !errors.Is(err, verifier.ErrUpstreamUnavailable) {
+			t.Fatalf("Verify() = %v, want %v", err, verifier.ErrUpstreamUnavailable)
+		}
+	}
+	if got := api.count(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestVerifyNotLinked(t *testing.T) {
+	api := &fakeAPI{t: t}
+	v := newVerifier(t, api, 1, 0)
+
+	err := v.Verify(context.Background(), 3, models.Task{Id: 1, Type: verifier.TypeTwitterFollow, Target: "100"})
+	if !errors.Is(err, verifier.ErrAccountNotLinked) {
+		t.Fatalf("Verify() = %v, want %v", err, verifier.ErrAccountNotLinked)
+	}
+	if got := api.count(); got != 0 {
+		t.Errorf("requests = %d, want 0", got)
+	}
+}
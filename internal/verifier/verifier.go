@@ -0,0 +1,95 @@
+package verifier
+
+import (
+	"context"
+	"denet/internal/lib/models"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Task types understood by the registry.
+const (
+	TypeNone                 = "none"
+	TypeManual               = "manual"
+	TypeTelegramSubscription = "telegram_subscription"
+	TypeTwitterFollow        = "twitter_follow"
+	TypeTwitterRetweet       = "twitter_retweet"
+	TypeTwitterLike          = "twitter_like"
+)
+
+var (
+	ErrNotVerified  = errors.New("task is not verified")
+	ErrManualReview = errors.New("task requires manual review")
+	ErrUnknownType  = errors.New("unknown task type")
+
+	ErrAccountNotLinked    = errors.New("external account is not linked")
+	ErrNotMember           = errors.New("user is not a member")
+	ErrUpstreamUnavailable = errors.New("verification service is unavailable")
+)
+
+// NeedsTarget reports whether tasks of taskType cannot be verified without
+// a target, such as the account to follow or the post to like.
+func NeedsTarget(taskType string) bool {
+	switch taskType {
+	case TypeTwitterFollow, TypeTwitterRetweet, TypeTwitterLike:
+		return true
+	}
+	return false
+}
+
+// Verifier confirms that a user has actually done what a task asks for
+// before its reward is credited.
+type Verifier interface {
+	Verify(ctx context.Context, userID int64, task models.Task) error
+}
+
+// Func adapts an ordinary function to the Verifier interface.
+type Func func(ctx context.Context, userID int64, task models.Task) error
+
+func (f Func) Verify(ctx context.Context, userID int64, task models.Task) error {
+	return f(ctx, userID, task)
+}
+
+// Registry dispatches verification to the Verifier registered for the task
+// type.
+type Registry struct {
+	mu        sync.RWMutex
+	verifiers map[string]Verifier
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		verifiers: make(map[string]Verifier),
+	}
+}
+
+func (r *Registry) Register(taskType string, v Verifier) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.verifiers[taskType] = v
+}
+
+func (r *Registry) Verify(ctx context.Context, userID int64, task models.Task) error {
+	r.mu.RLock()
+	v, ok := r.verifiers[task.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownType, task.Type)
+	}
+	return v.Verify(ctx, userID, task)
+}
+
+// None accepts every completion. It is used for tasks that need no proof.
+type None struct{}
+
+func (None) Verify(context.Context, int64, models.Task) error {
+	return nil
+}
+
+// Manual refuses automatic completion of tasks that a moderator has to check.
+type Manual struct{}
+
+func (Manual) Verify(context.Context, int64, models.Task) error {
+	return ErrManualReview
+}
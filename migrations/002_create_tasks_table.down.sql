@@ -0,0 +1 @@
+DROP TABLE tasks;
\ No newline at end of file
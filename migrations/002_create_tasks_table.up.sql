@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS tasks (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    title VARCHAR(255) NOT NULL,
+    reward INT NOT NULL CHECK (reward >= 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO tasks (slug, title, reward) VALUES
+    ('subscribe_telegram', 'Subscribe to the Telegram channel', 10),
+    ('follow_twitter', 'Follow us on Twitter', 10)
+ON CONFLICT (slug) DO NOTHING;
@@ -0,0 +1 @@
+DROP TABLE user_task_completions;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS user_task_completions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    points INT NOT NULL,
+    completed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_user_task_completions_user_task UNIQUE (user_id, task_id)
+);
+
+CREATE INDEX idx_user_task_completions_task_id ON user_task_completions(task_id);
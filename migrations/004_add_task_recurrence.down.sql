@@ -0,0 +1,15 @@
+DELETE FROM tasks WHERE slug IN ('daily_check_in', 'weekly_quiz');
+
+DROP TABLE user_task_states;
+
+DROP INDEX idx_user_task_completions_user_task;
+DELETE FROM user_task_completions c
+USING user_task_completions d
+WHERE c.user_id = d.user_id AND c.task_id = d.task_id AND c.id > d.id;
+ALTER TABLE user_task_completions
+    ADD CONSTRAINT uq_user_task_completions_user_task UNIQUE (user_id, task_id);
+
+ALTER TABLE tasks
+    DROP COLUMN recurrence,
+    DROP COLUMN cooldown_seconds,
+    DROP COLUMN streak_bonus;
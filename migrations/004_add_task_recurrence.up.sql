@@ -0,0 +1,27 @@
+ALTER TABLE tasks
+    ADD COLUMN recurrence VARCHAR(20) NOT NULL DEFAULT 'once'
+        CHECK (recurrence IN ('once', 'daily', 'weekly', 'cooldown')),
+    ADD COLUMN cooldown_seconds INT NOT NULL DEFAULT 0 CHECK (cooldown_seconds >= 0),
+    ADD COLUMN streak_bonus INT NOT NULL DEFAULT 0 CHECK (streak_bonus >= 0);
+
+ALTER TABLE user_task_completions DROP CONSTRAINT uq_user_task_completions_user_task;
+CREATE INDEX idx_user_task_completions_user_task ON user_task_completions(user_id, task_id);
+
+CREATE TABLE IF NOT EXISTS user_task_states (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    last_completed_at TIMESTAMP NOT NULL,
+    streak INT NOT NULL DEFAULT 1,
+    completions INT NOT NULL DEFAULT 1,
+    PRIMARY KEY (user_id, task_id)
+);
+
+INSERT INTO user_task_states (user_id, task_id, last_completed_at, streak, completions)
+SELECT user_id, task_id, MAX(completed_at), 1, COUNT(*)
+FROM user_task_completions
+GROUP BY user_id, task_id;
+
+INSERT INTO tasks (slug, title, reward, recurrence, streak_bonus) VALUES
+    ('daily_check_in', 'Daily check-in', 1, 'daily', 1),
+    ('weekly_quiz', 'Weekly quiz', 5, 'weekly', 2)
+ON CONFLICT (slug) DO NOTHING;
@@ -0,0 +1 @@
+DROP TABLE task_prerequisites;
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS task_prerequisites (
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    prerequisite_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, prerequisite_id),
+    CHECK (task_id <> prerequisite_id)
+);
+
+CREATE INDEX idx_task_prerequisites_prerequisite_id ON task_prerequisites(prerequisite_id);
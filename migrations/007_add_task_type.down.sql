@@ -0,0 +1 @@
+ALTER TABLE tasks DROP COLUMN type;
\ No newline at end of file
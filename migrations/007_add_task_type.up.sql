@@ -0,0 +1,4 @@
+ALTER TABLE tasks ADD COLUMN type VARCHAR(50) NOT NULL DEFAULT 'none';
+
+UPDATE tasks SET type = 'telegram_subscription' WHERE slug = 'subscribe_telegram';
+UPDATE tasks SET type = 'twitter_follow' WHERE slug = 'follow_twitter';
@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN telegram_id;
\ No newline at end of file
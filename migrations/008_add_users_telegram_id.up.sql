@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN telegram_id BIGINT UNIQUE;
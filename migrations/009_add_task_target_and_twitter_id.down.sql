@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN twitter_id;
+
+ALTER TABLE tasks DROP COLUMN target;
@@ -0,0 +1,3 @@
+ALTER TABLE tasks ADD COLUMN target VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE users ADD COLUMN twitter_id VARCHAR(64) UNIQUE;
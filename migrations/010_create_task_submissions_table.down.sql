@@ -0,0 +1 @@
+DROP TABLE task_submissions;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS task_submissions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    proof_url VARCHAR(2048) NOT NULL DEFAULT '',
+    proof_text TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected')),
+    reason TEXT NOT NULL DEFAULT '',
+    reviewed_by VARCHAR(100),
+    created_at TIMESTAMP NOT NULL,
+    reviewed_at TIMESTAMP
+);
+
+CREATE INDEX idx_task_submissions_status ON task_submissions(status, created_at);
+CREATE INDEX idx_task_submissions_user_id ON task_submissions(user_id);
+CREATE UNIQUE INDEX uq_task_submissions_pending ON task_submissions(user_id, task_id) WHERE status = 'pending';
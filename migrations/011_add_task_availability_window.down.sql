@@ -0,0 +1,6 @@
+ALTER TABLE tasks
+    DROP CONSTRAINT chk_tasks_window,
+    DROP COLUMN starts_at,
+    DROP COLUMN ends_at,
+    DROP COLUMN max_completions,
+    DROP COLUMN completions_count;
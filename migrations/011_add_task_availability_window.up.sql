@@ -0,0 +1,11 @@
+ALTER TABLE tasks
+    ADD COLUMN starts_at TIMESTAMP,
+    ADD COLUMN ends_at TIMESTAMP,
+    ADD COLUMN max_completions INT CHECK (max_completions > 0),
+    ADD COLUMN completions_count INT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT chk_tasks_window CHECK (starts_at IS NULL OR ends_at IS NULL OR starts_at < ends_at);
+
+UPDATE tasks t
+SET completions_count = c.users
+FROM (SELECT task_id, COUNT(*) AS users FROM user_task_states GROUP BY task_id) c
+WHERE c.task_id = t.id;
@@ -0,0 +1,8 @@
+DELETE FROM tasks WHERE slug IN ('invite_5_friends', 'check_in_7_times');
+
+DROP TABLE user_task_progress;
+
+DROP INDEX idx_tasks_counter;
+ALTER TABLE tasks
+    DROP COLUMN counter,
+    DROP COLUMN target_value;
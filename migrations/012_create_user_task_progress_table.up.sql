@@ -0,0 +1,18 @@
+ALTER TABLE tasks
+    ADD COLUMN counter VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN target_value INT NOT NULL DEFAULT 0 CHECK (target_value >= 0);
+
+CREATE TABLE IF NOT EXISTS user_task_progress (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    current INT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP,
+    PRIMARY KEY (user_id, task_id)
+);
+
+CREATE INDEX idx_tasks_counter ON tasks(counter) WHERE counter <> '';
+
+INSERT INTO tasks (slug, title, reward, counter, target_value) VALUES
+    ('invite_5_friends', 'Invite 5 friends', 25, 'referrals', 5),
+    ('check_in_7_times', 'Check in 7 times', 10, 'check_ins', 7)
+ON CONFLICT (slug) DO NOTHING;
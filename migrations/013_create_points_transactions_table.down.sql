@@ -0,0 +1 @@
+DROP TABLE points_transactions;
\ No newline at end of file
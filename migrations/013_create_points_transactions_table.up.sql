@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS points_transactions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    delta BIGINT NOT NULL CHECK (delta <> 0),
+    reason VARCHAR(20) NOT NULL,
+    task_id INT REFERENCES tasks(id) ON DELETE SET NULL,
+    referral_id INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_points_transactions_user_id ON points_transactions(user_id, id);
+
+-- Balances accumulated before the ledger existed are carried over as one
+-- opening entry per user, so the ledger sum matches users.points.
+INSERT INTO points_transactions (user_id, delta, reason, created_at)
+SELECT id, points, 'opening', (now() AT TIME ZONE 'UTC')
+FROM users
+WHERE COALESCE(points, 0) <> 0;
@@ -0,0 +1 @@
+ALTER TABLE points_transactions DROP COLUMN counterparty_id, DROP COLUMN memo;
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE points_transactions
+    ADD COLUMN counterparty_id INT REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN memo VARCHAR(140) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_points_transactions_transfers ON points_transactions(user_id, created_at)
+    WHERE reason = 'transfer' AND delta < 0;
@@ -0,0 +1,4 @@
+ALTER TABLE points_transactions DROP COLUMN redemption_id;
+
+DROP TABLE redemptions;
+DROP TABLE rewards;
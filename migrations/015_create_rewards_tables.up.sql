@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS rewards (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    price INT NOT NULL CHECK (price > 0),
+    stock INT NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+
+CREATE TABLE IF NOT EXISTS redemptions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reward_id INT NOT NULL REFERENCES rewards(id) ON DELETE RESTRICT,
+    price INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'fulfilled', 'cancelled')),
+    note TEXT NOT NULL DEFAULT '',
+    updated_by VARCHAR(100),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX idx_redemptions_user_id ON redemptions(user_id, created_at);
+CREATE INDEX idx_redemptions_status ON redemptions(status, created_at);
+
+ALTER TABLE points_transactions
+    ADD COLUMN redemption_id INT REFERENCES redemptions(id) ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+DROP TABLE reward_codes;
+
+ALTER TABLE rewards DROP COLUMN code_pool;
@@ -0,0 +1,13 @@
+ALTER TABLE rewards ADD COLUMN code_pool BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS reward_codes (
+    id SERIAL PRIMARY KEY,
+    reward_id INT NOT NULL REFERENCES rewards(id) ON DELETE CASCADE,
+    code VARCHAR(255) NOT NULL,
+    redemption_id INT UNIQUE REFERENCES redemptions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL,
+    assigned_at TIMESTAMP,
+    UNIQUE (reward_id, code)
+);
+
+CREATE INDEX idx_reward_codes_unused ON reward_codes(reward_id, id) WHERE assigned_at IS NULL;
@@ -0,0 +1,8 @@
+DROP INDEX idx_points_transactions_expiring;
+DROP INDEX idx_points_transactions_unspent;
+
+ALTER TABLE points_transactions
+    DROP COLUMN expires_at,
+    DROP COLUMN remaining;
+
+ALTER TABLE tasks DROP COLUMN expiry_days;
@@ -0,0 +1,23 @@
+ALTER TABLE tasks ADD COLUMN expiry_days INT NOT NULL DEFAULT 0 CHECK (expiry_days >= 0);
+
+ALTER TABLE points_transactions
+    ADD COLUMN expires_at TIMESTAMP,
+    ADD COLUMN remaining BIGINT NOT NULL DEFAULT 0 CHECK (remaining >= 0);
+
+-- remaining is the unspent part of a credit. Spending so far is charged
+-- against the oldest credits first.
+WITH credits AS (
+    SELECT c.id, c.delta,
+        SUM(c.delta) OVER (PARTITION BY c.user_id ORDER BY c.id) AS earned,
+        (SELECT COALESCE(SUM(-d.delta), 0) FROM points_transactions d
+            WHERE d.user_id = c.user_id AND d.delta < 0) AS spent
+    FROM points_transactions c
+    WHERE c.delta > 0
+)
+UPDATE points_transactions pt
+SET remaining = GREATEST(0, LEAST(credits.delta, credits.earned - credits.spent))
+FROM credits
+WHERE pt.id = credits.id;
+
+CREATE INDEX idx_points_transactions_unspent ON points_transactions(user_id, id) WHERE remaining > 0;
+CREATE INDEX idx_points_transactions_expiring ON points_transactions(expires_at) WHERE remaining > 0;
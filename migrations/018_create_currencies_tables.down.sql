@@ -0,0 +1,5 @@
+ALTER TABLE points_transactions DROP COLUMN currency;
+
+DROP TABLE task_rewards;
+DROP TABLE user_balances;
+DROP TABLE currencies;
@@ -0,0 +1,28 @@
+-- Currencies are configured in the application config and upserted here on
+-- startup. Points stay in users.points; other currencies live in
+-- user_balances.
+CREATE TABLE IF NOT EXISTS currencies (
+    code VARCHAR(20) PRIMARY KEY,
+    title VARCHAR(100) NOT NULL DEFAULT ''
+);
+
+INSERT INTO currencies (code, title) VALUES ('points', 'Points') ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS user_balances (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    currency VARCHAR(20) NOT NULL REFERENCES currencies(code) CHECK (currency <> 'points'),
+    balance BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, currency)
+);
+
+CREATE INDEX idx_user_balances_currency ON user_balances(currency, balance DESC);
+
+CREATE TABLE IF NOT EXISTS task_rewards (
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    currency VARCHAR(20) NOT NULL REFERENCES currencies(code) CHECK (currency <> 'points'),
+    amount INT NOT NULL CHECK (amount > 0),
+    PRIMARY KEY (task_id, currency)
+);
+
+ALTER TABLE points_transactions
+    ADD COLUMN currency VARCHAR(20) NOT NULL DEFAULT 'points' REFERENCES currencies(code);
@@ -0,0 +1 @@
+ALTER TABLE points_transactions DROP COLUMN actor;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- actor is the admin who posted a manual adjustment.
+ALTER TABLE points_transactions ADD COLUMN actor VARCHAR(100);
@@ -0,0 +1,3 @@
+ALTER TABLE points_transactions DROP COLUMN boosts;
+ALTER TABLE points_transactions DROP COLUMN base_delta;
+ALTER TABLE users DROP COLUMN tier;
\ No newline at end of file
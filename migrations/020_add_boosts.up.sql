@@ -0,0 +1,7 @@
+-- tier groups users for tier-specific boosts.
+ALTER TABLE users ADD COLUMN tier VARCHAR(20) NOT NULL DEFAULT '';
+
+-- base_delta is the amount before boosts and is NULL when none applied;
+-- boosts lists the names of the applied rules.
+ALTER TABLE points_transactions ADD COLUMN base_delta BIGINT;
+ALTER TABLE points_transactions ADD COLUMN boosts TEXT[];
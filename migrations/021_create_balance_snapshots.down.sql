@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_points_transactions_currency_created;
+DROP TABLE IF EXISTS snapshot_entries;
+DROP TABLE IF EXISTS balance_snapshots;
\ No newline at end of file
@@ -0,0 +1,23 @@
+-- A snapshot materialises every positive balance in one currency as of a
+-- point in time, so that airdrops computed from it do not change when later
+-- entries are posted.
+CREATE TABLE IF NOT EXISTS balance_snapshots (
+    id SERIAL PRIMARY KEY,
+    currency VARCHAR(20) NOT NULL REFERENCES currencies(code),
+    taken_at TIMESTAMP NOT NULL,
+    users INT NOT NULL DEFAULT 0,
+    total BIGINT NOT NULL DEFAULT 0,
+    created_by VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS snapshot_entries (
+    snapshot_id INT NOT NULL REFERENCES balance_snapshots(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rank INT NOT NULL,
+    balance BIGINT NOT NULL,
+    PRIMARY KEY (snapshot_id, user_id)
+);
+
+CREATE INDEX idx_snapshot_entries_rank ON snapshot_entries(snapshot_id, rank);
+CREATE INDEX idx_points_transactions_currency_created ON points_transactions(currency, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS airdrop_claims;
+DROP TABLE IF EXISTS airdrops;
\ No newline at end of file
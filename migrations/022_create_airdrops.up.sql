@@ -0,0 +1,24 @@
+-- An airdrop is a Merkle tree built from a balance snapshot. Amounts are
+-- uint256 token amounts and proofs are 0x-prefixed hashes from leaf to root.
+-- Claims go to users.wallet_address, which is added by 023 together with
+-- wallet linking, so airdrops can only be built once that is applied.
+CREATE TABLE IF NOT EXISTS airdrops (
+    id SERIAL PRIMARY KEY,
+    snapshot_id INT NOT NULL REFERENCES balance_snapshots(id) ON DELETE RESTRICT,
+    root CHAR(66) NOT NULL,
+    decimals INT NOT NULL DEFAULT 0,
+    claims INT NOT NULL,
+    total NUMERIC(78, 0) NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS airdrop_claims (
+    airdrop_id INT NOT NULL REFERENCES airdrops(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    claim_index INT NOT NULL,
+    account VARCHAR(42) NOT NULL,
+    amount NUMERIC(78, 0) NOT NULL,
+    proof TEXT[] NOT NULL,
+    PRIMARY KEY (airdrop_id, user_id),
+    UNIQUE (airdrop_id, claim_index)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_wallet_address;
+DROP TABLE IF EXISTS wallet_challenges;
+ALTER TABLE users DROP COLUMN wallet_address;
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- A wallet challenge is the message a user signs to prove they own address.
+-- Each user has at most one outstanding challenge, consumed on success.
+CREATE TABLE IF NOT EXISTS wallet_challenges (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    address VARCHAR(42) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    message TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- wallet_address is the lowercase 0x-prefixed address a user receives
+-- on-chain rewards at. It is only set once the user signed a challenge.
+ALTER TABLE users ADD COLUMN wallet_address VARCHAR(42);
+
+CREATE UNIQUE INDEX idx_users_wallet_address ON users(wallet_address);
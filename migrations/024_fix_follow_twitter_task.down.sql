@@ -0,0 +1 @@
+UPDATE tasks SET type = 'twitter_follow' WHERE slug = 'follow_twitter' AND type = 'manual' AND target = '';
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- 007 made the seeded follow_twitter task a twitter_follow task without a
+-- target, which can never be verified. Moderators review it until the X
+-- account ID to follow is set as its target.
+UPDATE tasks SET type = 'manual' WHERE slug = 'follow_twitter' AND type = 'twitter_follow' AND target = '';